@@ -59,23 +59,34 @@ func main() {
 	}
 
 	// Services
-	aggregationService := service.NewAggregationService(db.Pool(), nil, cfg.CacheTTL)
+	aggregationService := service.NewAggregationService(db.Pool(), cacheService.Client(), cfg.CacheTTL)
 	tradeService := service.NewTradeService(db.Pool())
-	analysisService := service.NewAnalysisService(db.Pool())
+	analysisService := service.NewAnalysisService(db.Pool(), cacheService)
+	candleService := service.NewCandleService(db.Pool(), cacheService, cfg.CandleFill, cfg.CandleCacheIntervals)
+	alertService := service.NewAlertService(db.Pool(), cfg.AlertWebhookURLs, cfg.AlertWebhookRetries, cfg.AlertWebhookTimeout)
+	portfolioService := service.NewPortfolioService(db.Pool())
+	indexService := service.NewIndexService(db.Pool())
 
 	// Ingestion
 	parser := ingestion.NewParser(cfg.BatchSize, cfg.Workers)
 	loader := ingestion.NewBulkLoader(db.Pool(), cfg.BatchSize)
 	ingestionService := service.NewIngestionService(parser, loader)
+	reconciliationService := service.NewReconciliationService(db.Pool(), parser)
 
 	// Handler
 	handler := api.NewHandler(
+		cfg,
 		db,
 		cacheService,
 		aggregationService,
 		tradeService,
 		analysisService,
 		ingestionService,
+		reconciliationService,
+		candleService,
+		alertService,
+		portfolioService,
+		indexService,
 	)
 
 	// Fiber app
@@ -3,22 +3,29 @@ package main
 import (
 	"archive/zip"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
 	"github.com/spf13/cobra"
 
 	"github.com/jeovahfialho/b3-analyzer/internal/config"
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
 	"github.com/jeovahfialho/b3-analyzer/internal/ingestion"
 	"github.com/jeovahfialho/b3-analyzer/internal/service"
 	"github.com/jeovahfialho/b3-analyzer/internal/storage/cache"
 	"github.com/jeovahfialho/b3-analyzer/internal/storage/postgres"
+	"github.com/jeovahfialho/b3-analyzer/pkg/backtest"
+	"github.com/jeovahfialho/b3-analyzer/pkg/indicators"
 )
 
 func main() {
@@ -82,9 +89,101 @@ Aceita múltiplos arquivos e suporta wildcards.`,
 
 	queryCmd.Flags().StringP("start-date", "s", "", "Data inicial (YYYY-MM-DD)")
 
+	var historyCmd = &cobra.Command{
+		Use:   "history [ticker]",
+		Short: "Mostra o histórico diário de um ticker",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			startDate, _ := cmd.Flags().GetString("start-date")
+			endDate, _ := cmd.Flags().GetString("end-date")
+			granularity, _ := cmd.Flags().GetString("granularity")
+			return showHistory(args[0], startDate, endDate, granularity)
+		},
+	}
+
+	historyCmd.Flags().StringP("start-date", "s", "", "Data inicial (YYYY-MM-DD)")
+	historyCmd.Flags().StringP("end-date", "e", "", "Data final (YYYY-MM-DD)")
+	historyCmd.Flags().StringP("granularity", "g", service.GranularityDay, "Granularidade (day, week, month, quarter, year)")
+
+	var indicatorsCmd = &cobra.Command{
+		Use:   "indicators [ticker]",
+		Short: "Calcula indicadores técnicos sobre a série diária",
+		Long: `Calcula indicadores técnicos sobre a série diária de um ticker.
+Exemplo: indicators PETR4 --names sma:20,ema:50,rsi:14,macd,bbands:20:2,atr:14`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names, _ := cmd.Flags().GetString("names")
+			startDate, _ := cmd.Flags().GetString("start-date")
+			endDate, _ := cmd.Flags().GetString("end-date")
+			return showIndicators(args[0], names, startDate, endDate)
+		},
+	}
+
+	indicatorsCmd.Flags().StringP("names", "n", "sma:20,rsi:14", "Indicadores separados por vírgula")
+	indicatorsCmd.Flags().StringP("start-date", "s", "", "Data inicial (YYYY-MM-DD)")
+	indicatorsCmd.Flags().StringP("end-date", "e", "", "Data final (YYYY-MM-DD)")
+
+	var backtestCmd = &cobra.Command{
+		Use:   "backtest [strategy-file]",
+		Short: "Simula uma estratégia sobre os pregões armazenados",
+		Long: `Simula uma estratégia declarativa sobre daily_aggregations. O arquivo JSON
+tem o mesmo formato do corpo de POST /api/v1/backtests (tickers, start_date,
+end_date e strategy); --tickers, --start-date e --end-date sobrescrevem o arquivo.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tickers, _ := cmd.Flags().GetString("tickers")
+			startDate, _ := cmd.Flags().GetString("start-date")
+			endDate, _ := cmd.Flags().GetString("end-date")
+			asJSON, _ := cmd.Flags().GetBool("json")
+			return runBacktest(args[0], tickers, startDate, endDate, asJSON)
+		},
+	}
+
+	backtestCmd.Flags().StringP("tickers", "t", "", "Tickers separados por vírgula")
+	backtestCmd.Flags().StringP("start-date", "s", "", "Data inicial (YYYY-MM-DD)")
+	backtestCmd.Flags().StringP("end-date", "e", "", "Data final (YYYY-MM-DD)")
+	backtestCmd.Flags().Bool("json", false, "Imprime o resultado completo em JSON")
+
+	var screenCmd = &cobra.Command{
+		Use:   "screen",
+		Short: "Filtra os tickers pelas métricas do período",
+		Long: `Filtra o universo de tickers por último fechamento, volume médio,
+volatilidade anualizada, retorno e amplitude no período. Sem datas, usa os
+últimos --days pregões. Exemplo:
+screen --min-volume 1000000 --min-return 5 --sort return --limit 20`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter, err := screenFilterFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			asJSON, _ := cmd.Flags().GetBool("json")
+			return runScreen(filter, asJSON)
+		},
+	}
+
+	screenCmd.Flags().StringP("tickers", "t", "", "Restringe a tickers separados por vírgula")
+	screenCmd.Flags().StringP("start-date", "s", "", "Data inicial (YYYY-MM-DD)")
+	screenCmd.Flags().StringP("end-date", "e", "", "Data final (YYYY-MM-DD)")
+	screenCmd.Flags().IntP("days", "d", 0, "Últimos N pregões (padrão 20 sem datas)")
+	screenCmd.Flags().Float64("min-price", 0, "Último fechamento mínimo")
+	screenCmd.Flags().Float64("max-price", 0, "Último fechamento máximo")
+	screenCmd.Flags().Int64("min-volume", 0, "Volume médio diário mínimo")
+	screenCmd.Flags().Int64("max-volume", 0, "Volume médio diário máximo")
+	screenCmd.Flags().Float64("min-volatility", 0, "Volatilidade anualizada mínima (%)")
+	screenCmd.Flags().Float64("max-volatility", 0, "Volatilidade anualizada máxima (%)")
+	screenCmd.Flags().Float64("min-return", 0, "Retorno mínimo no período (%)")
+	screenCmd.Flags().Float64("max-return", 0, "Retorno máximo no período (%)")
+	screenCmd.Flags().Float64("min-range", 0, "Amplitude mínima no período (%)")
+	screenCmd.Flags().Float64("max-range", 0, "Amplitude máxima no período (%)")
+	screenCmd.Flags().String("sort", "avg_volume", "Ordenação: ticker, last_close, max_price, avg_volume, max_volume, volatility, return ou range_percent")
+	screenCmd.Flags().String("order", "", "Direção: asc ou desc")
+	screenCmd.Flags().IntP("limit", "l", 50, "Resultados por página")
+	screenCmd.Flags().String("cursor", "", "Cursor da próxima página")
+	screenCmd.Flags().Bool("json", false, "Imprime o resultado completo em JSON")
+
 	var refreshCmd = &cobra.Command{
 		Use:   "refresh",
-		Short: "Atualiza materialized views",
+		Short: "Reconstrói daily_aggregations a partir de trades (reparo)",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return refreshViews()
 		},
@@ -98,7 +197,26 @@ Aceita múltiplos arquivos e suporta wildcards.`,
 		},
 	}
 
-	rootCmd.AddCommand(downloadCmd, listCmd, loadCmd, queryCmd, refreshCmd, healthCmd)
+	var verifyCmd = &cobra.Command{
+		Use:   "verify",
+		Short: "Confere os dados carregados com o arquivo de origem",
+		Long: `Relê o arquivo TXT ou ZIP de uma data e compara, por ticker, a quantidade
+de negócios, a quantidade negociada e a soma dos preços com as tabelas trades
+e daily_aggregations. Retorna código de saída diferente de zero se houver divergência.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dateStr, _ := cmd.Flags().GetString("date")
+			filePath, _ := cmd.Flags().GetString("file")
+			dataDir, _ := cmd.Flags().GetString("dir")
+			return verifyLoad(dateStr, filePath, dataDir)
+		},
+	}
+
+	verifyCmd.Flags().String("date", "", "Data do pregão (YYYY-MM-DD)")
+	verifyCmd.Flags().StringP("file", "f", "", "Arquivo TXT ou ZIP de origem (padrão: procura no diretório)")
+	verifyCmd.Flags().StringP("dir", "d", "./data", "Diretório dos dados")
+	verifyCmd.MarkFlagRequired("date")
+
+	rootCmd.AddCommand(downloadCmd, listCmd, loadCmd, queryCmd, historyCmd, indicatorsCmd, backtestCmd, screenCmd, refreshCmd, healthCmd, verifyCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -308,22 +426,92 @@ func refreshViews() error {
 	}
 	defer pool.Close()
 
-	fmt.Println("🔄 Atualizando materialized views...")
+	fmt.Println("🔄 Reconstruindo agregações diárias...")
 
-	_, err = pool.Exec(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY daily_aggregations")
+	redisCache := connectRedis(cfg)
+	if redisCache != nil {
+		defer redisCache.Close()
+	}
+
+	start := time.Now()
+	aggregationService := service.NewAggregationService(pool, redisCache.Client(), cfg.CacheTTL)
+	rows, err := aggregationService.RebuildDailyAggregations(ctx)
 	if err != nil {
-		return fmt.Errorf("erro ao atualizar views: %w", err)
+		return fmt.Errorf("erro ao reconstruir agregações: %w", err)
 	}
 
-	fmt.Println("✅ Views atualizadas com sucesso!")
+	fmt.Printf("✅ %d agregações reconstruídas em %s\n", rows, time.Since(start).Round(time.Millisecond))
+
+	updateIndexes(ctx, pool)
+	evaluateAlerts(ctx, cfg, pool, nil)
+	warmIntradayProfiles(ctx, cfg, pool)
 	return nil
 }
 
+// invalidateAggregationCache descarta agregações em cache após a carga;
+// sem Redis, não há o que invalidar.
+func invalidateAggregationCache(ctx context.Context, cfg *config.Config, pool *pgxpool.Pool) {
+	redisCache := connectRedis(cfg)
+	if redisCache == nil {
+		return
+	}
+	defer redisCache.Close()
+
+	service.NewAggregationService(pool, redisCache.Client(), cfg.CacheTTL).InvalidateCache(ctx)
+}
+
+// updateIndexes recalcula os índices customizados cujos pregões mudaram;
+// falhas são apenas reportadas.
+func updateIndexes(ctx context.Context, pool *pgxpool.Pool) {
+	updated, err := service.NewIndexService(pool).UpdateIndexes(ctx)
+	if err != nil {
+		fmt.Printf("⚠️ Erro ao atualizar índices: %v\n", err)
+		return
+	}
+	if updated > 0 {
+		fmt.Printf("📈 %d índice(s) atualizado(s)\n", updated)
+	}
+}
+
+// warmIntradayProfiles recalcula o perfil intradiário do mercado no cache;
+// sem Redis, não há o que aquecer.
+func warmIntradayProfiles(ctx context.Context, cfg *config.Config, pool *pgxpool.Pool) {
+	redisCache := connectRedis(cfg)
+	if redisCache == nil {
+		return
+	}
+	defer redisCache.Close()
+
+	candleService := service.NewCandleService(pool, redisCache, cfg.CandleFill, cfg.CandleCacheIntervals)
+	if err := candleService.WarmIntradayProfiles(ctx); err != nil && !errors.Is(err, service.ErrNotFound) {
+		fmt.Printf("⚠️ Erro ao atualizar perfis intradiários: %v\n", err)
+	}
+}
+
+// evaluateAlerts avalia as regras de alerta em cada pregão carregado (ou
+// no último, sem datas); falhas são apenas reportadas para não invalidar a
+// carga.
+func evaluateAlerts(ctx context.Context, cfg *config.Config, pool *pgxpool.Pool, dates []time.Time) {
+	alertService := service.NewAlertService(pool, cfg.AlertWebhookURLs, cfg.AlertWebhookRetries, cfg.AlertWebhookTimeout)
+
+	evaluations, err := alertService.EvaluateDates(ctx, dates)
+	for _, evaluation := range evaluations {
+		fmt.Printf("🔔 %d regra(s) avaliada(s), %d alerta(s) disparado(s) em %s\n",
+			evaluation.RulesEvaluated, len(evaluation.Fired), evaluation.Date.Format("02/01/2006"))
+		for _, event := range evaluation.Fired {
+			fmt.Printf("   %s\n", event.Message)
+		}
+	}
+	if err != nil && !errors.Is(err, service.ErrNotFound) {
+		fmt.Printf("⚠️ Erro ao avaliar alertas: %v\n", err)
+	}
+}
+
 func checkHealth() error {
 	ctx := context.Background()
 	cfg := config.Load()
 
-	fmt.Println("🏥 Verificando saúde do sistema...\n")
+	fmt.Print("🏥 Verificando saúde do sistema...\n\n")
 
 	fmt.Print("PostgreSQL: ")
 	pool, err := connectDB(cfg)
@@ -390,10 +578,14 @@ func loadFiles(files []string) error {
 	}
 
 	var totalRecords int64
+	var dates []time.Time
+	failed := 0
 	for i := 0; i < len(files); i++ {
 		result := <-results
+		dates = append(dates, result.Dates...)
 		if result.Error != nil {
 			fmt.Printf("❌ Erro em %s: %v\n", result.FilePath, result.Error)
+			failed++
 		} else {
 			fmt.Printf("✅ Carregados %d registros de %s\n", result.RecordsCount, result.FilePath)
 			totalRecords += result.RecordsCount
@@ -401,13 +593,76 @@ func loadFiles(files []string) error {
 	}
 
 	fmt.Printf("\n📊 Total: %d registros carregados\n", totalRecords)
+	if failed > 0 {
+		fmt.Println("⚠️ Cargas com erro podem ter gravado parte dos negócios; confira com `verify` antes de carregar o arquivo de novo")
+	}
 
-	fmt.Println("\n🔄 Atualizando agregações...")
-	pool.Exec(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY daily_aggregations")
-
+	// daily_aggregations já foi atualizada pela carga, pregão a pregão
+	invalidateAggregationCache(ctx, cfg, pool)
+	updateIndexes(ctx, pool)
+	evaluateAlerts(ctx, cfg, pool, dates)
+	warmIntradayProfiles(ctx, cfg, pool)
 	return nil
 }
 
+func verifyLoad(dateStr, filePath, dataDir string) error {
+	ctx := context.Background()
+	cfg := config.Load()
+
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return fmt.Errorf("data inválida: %w", err)
+	}
+
+	if filePath == "" {
+		filePath, err = ingestion.FindSourceFile(dataDir, date)
+		if err != nil {
+			return err
+		}
+	}
+
+	pool, err := connectDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	parser := ingestion.NewParser(cfg.BatchSize, cfg.Workers)
+	reconciliationService := service.NewReconciliationService(pool, parser)
+
+	fmt.Printf("🔎 Conferindo %s com os dados de %s...\n", filepath.Base(filePath), date.Format("02/01/2006"))
+
+	report, err := reconciliationService.Reconcile(ctx, filePath, date)
+	if err != nil {
+		return fmt.Errorf("erro na reconciliação: %w", err)
+	}
+
+	fmt.Printf("\n📊 Registros no arquivo: %d\n", report.SourceRecords)
+	if report.ParseErrors > 0 {
+		fmt.Printf("⚠️  Linhas com erro de parse: %d\n", report.ParseErrors)
+	}
+	fmt.Printf("📋 Tickers verificados: %d\n", report.TickersChecked)
+
+	if report.Consistent {
+		fmt.Println("\n✅ Nenhuma divergência encontrada")
+		return nil
+	}
+
+	fmt.Printf("\n❌ %d ticker(s) com divergência:\n", len(report.Discrepancies))
+	for _, d := range report.Discrepancies {
+		fmt.Printf("\n%s\n", d.Ticker)
+		for i, issue := range d.Issues {
+			prefix := "├─"
+			if i == len(d.Issues)-1 {
+				prefix = "└─"
+			}
+			fmt.Printf("%s %s\n", prefix, issue)
+		}
+	}
+
+	return fmt.Errorf("reconciliação falhou: %d ticker(s) divergentes", len(report.Discrepancies))
+}
+
 func queryTicker(ticker string, startDateStr string) error {
 	ctx := context.Background()
 	cfg := config.Load()
@@ -447,6 +702,390 @@ func queryTicker(ticker string, startDateStr string) error {
 	return nil
 }
 
+func showHistory(ticker, startDateStr, endDateStr, granularity string) error {
+	ctx := context.Background()
+	cfg := config.Load()
+
+	if !service.ValidGranularity(granularity) {
+		return fmt.Errorf("granularidade inválida: %s (use day, week, month, quarter ou year)", granularity)
+	}
+
+	startDate, err := parseOptionalDate(startDateStr)
+	if err != nil {
+		return err
+	}
+	endDate, err := parseOptionalDate(endDateStr)
+	if err != nil {
+		return err
+	}
+
+	pool, err := connectDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	tradeService := service.NewTradeService(pool)
+
+	history, err := tradeService.GetTickerHistoryByPeriod(ctx, ticker, granularity, startDate, endDate)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar histórico: %w", err)
+	}
+
+	if len(history) == 0 {
+		fmt.Printf("❌ Nenhum dado encontrado para %s\n", ticker)
+		return nil
+	}
+
+	if granularity == service.GranularityDay {
+		fmt.Printf("📈 Histórico de %s (%d pregões)\n\n", ticker, len(history))
+	} else {
+		fmt.Printf("📈 Histórico de %s por %s (%d períodos, * = parcial)\n\n", ticker, granularity, len(history))
+	}
+	fmt.Printf("%-11s %10s %10s %10s %10s %10s %15s %18s %9s %8s\n",
+		"Data", "Abertura", "Máxima", "Mínima", "Fech.", "VWAP", "Volume", "Volume R$", "Negócios", "Bloco%")
+
+	for _, day := range history {
+		label := day.DataNegocio.Format("02/01/2006")
+		if day.Partial {
+			label += "*"
+		}
+		fmt.Printf("%-11s %10s %10s %10s %10s %10s %15s %18s %9d %8.2f\n",
+			label,
+			day.OpenPrice.StringFixed(2),
+			day.MaxPrice.StringFixed(2),
+			day.MinPrice.StringFixed(2),
+			day.ClosePrice.StringFixed(2),
+			day.VWAP.StringFixed(2),
+			formatNumber(day.TotalVolume),
+			formatNumber(day.FinancialVolume.IntPart()),
+			day.TradeCount,
+			day.BlockVolumePercent)
+	}
+
+	return nil
+}
+
+func showIndicators(ticker, names, startDateStr, endDateStr string) error {
+	ctx := context.Background()
+	cfg := config.Load()
+
+	specs, err := indicators.ParseSpecs(names)
+	if err != nil {
+		return err
+	}
+
+	startDate, err := parseOptionalDate(startDateStr)
+	if err != nil {
+		return err
+	}
+	endDate, err := parseOptionalDate(endDateStr)
+	if err != nil {
+		return err
+	}
+
+	pool, err := connectDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	analysisService := service.NewAnalysisService(pool, nil)
+
+	series, err := analysisService.GetIndicators(ctx, ticker, specs, startDate, endDate)
+	if err != nil {
+		return fmt.Errorf("erro ao calcular indicadores: %w", err)
+	}
+
+	type column struct {
+		header string
+		values []*float64
+	}
+
+	var columns []column
+	for _, spec := range specs {
+		outputs := series.Indicators[spec.Key()]
+		names := make([]string, 0, len(outputs))
+		for name := range outputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			header := spec.Key()
+			if name != "value" {
+				header += "." + name
+			}
+			columns = append(columns, column{header: header, values: outputs[name]})
+		}
+	}
+
+	fmt.Printf("📐 Indicadores de %s (%d pregões)\n\n", ticker, len(series.Dates))
+	fmt.Printf("%-10s %10s", "Data", "Fech.")
+	for _, col := range columns {
+		fmt.Printf(" %18s", col.header)
+	}
+	fmt.Println()
+
+	for i, date := range series.Dates {
+		fmt.Printf("%-10s %10.2f", date.Format("02/01/2006"), series.Close[i])
+		for _, col := range columns {
+			if col.values[i] == nil {
+				fmt.Printf(" %18s", "-")
+			} else {
+				fmt.Printf(" %18.4f", *col.values[i])
+			}
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+type backtestFile struct {
+	Tickers   []string          `json:"tickers"`
+	StartDate string            `json:"start_date"`
+	EndDate   string            `json:"end_date"`
+	Strategy  backtest.Strategy `json:"strategy"`
+}
+
+func runBacktest(strategyFile, tickersFlag, startDateStr, endDateStr string, asJSON bool) error {
+	ctx := context.Background()
+	cfg := config.Load()
+
+	data, err := os.ReadFile(strategyFile)
+	if err != nil {
+		return fmt.Errorf("erro ao ler estratégia: %w", err)
+	}
+
+	var file backtestFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("estratégia inválida: %w", err)
+	}
+
+	if tickersFlag != "" {
+		file.Tickers = strings.Split(tickersFlag, ",")
+	}
+	if startDateStr != "" {
+		file.StartDate = startDateStr
+	}
+	if endDateStr != "" {
+		file.EndDate = endDateStr
+	}
+
+	var tickers []string
+	for _, ticker := range file.Tickers {
+		if ticker = strings.ToUpper(strings.TrimSpace(ticker)); ticker != "" {
+			tickers = append(tickers, ticker)
+		}
+	}
+	if len(tickers) == 0 {
+		return fmt.Errorf("informe ao menos um ticker")
+	}
+
+	startDate, err := time.Parse("2006-01-02", file.StartDate)
+	if err != nil {
+		return fmt.Errorf("start_date inválida: %w", err)
+	}
+	endDate, err := time.Parse("2006-01-02", file.EndDate)
+	if err != nil {
+		return fmt.Errorf("end_date inválida: %w", err)
+	}
+
+	pool, err := connectDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	analysisService := service.NewAnalysisService(pool, nil)
+
+	result, err := analysisService.RunBacktest(ctx, tickers, startDate, endDate, file.Strategy)
+	if err != nil {
+		return fmt.Errorf("erro ao executar backtest: %w", err)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	m := result.Metrics
+	optional := func(v *float64, format string) string {
+		if v == nil {
+			return "-"
+		}
+		return fmt.Sprintf(format, *v)
+	}
+
+	fmt.Printf("🧪 Backtest %s: %s de %s a %s\n\n", result.Strategy.Name, strings.Join(tickers, ", "),
+		startDate.Format("02/01/2006"), endDate.Format("02/01/2006"))
+	fmt.Printf("Capital inicial:  R$ %.2f\n", m.InitialCapital)
+	fmt.Printf("Patrimônio final: R$ %.2f\n", m.FinalEquity)
+	fmt.Printf("Retorno total:    %.2f%%\n", m.TotalReturn)
+	fmt.Printf("CAGR:             %s\n", optional(m.CAGR, "%.2f%%"))
+	fmt.Printf("Sharpe:           %s\n", optional(m.Sharpe, "%.2f"))
+	fmt.Printf("Drawdown máximo:  %.2f%%\n", m.MaxDrawdown)
+	fmt.Printf("Taxa de acerto:   %s\n", optional(m.HitRate, "%.1f%%"))
+	fmt.Printf("Trades:           %d\n", m.Trades)
+	fmt.Printf("Exposição:        %.1f%% de %d pregões\n", m.Exposure, m.Sessions)
+
+	if len(result.Trades) == 0 {
+		return nil
+	}
+
+	fmt.Printf("\n%-8s %-10s %10s %-10s %10s %10s %12s %8s  %s\n",
+		"Ticker", "Entrada", "Preço", "Saída", "Preço", "Qtd", "P&L", "Ret.%", "Motivo")
+	for _, trade := range result.Trades {
+		fmt.Printf("%-8s %-10s %10.2f %-10s %10.2f %10d %12.2f %8.2f  %s\n",
+			trade.Ticker,
+			trade.EntryDate.Format("02/01/2006"), trade.EntryPrice,
+			trade.ExitDate.Format("02/01/2006"), trade.ExitPrice,
+			trade.Quantity, trade.PnL, trade.ReturnPct, trade.ExitReason)
+	}
+
+	return nil
+}
+
+func screenFilterFromFlags(cmd *cobra.Command) (domain.AggregationFilter, error) {
+	var filter domain.AggregationFilter
+	flags := cmd.Flags()
+
+	tickers, _ := flags.GetString("tickers")
+	for _, ticker := range strings.Split(tickers, ",") {
+		if ticker = strings.TrimSpace(ticker); ticker != "" {
+			filter.Tickers = append(filter.Tickers, ticker)
+		}
+	}
+
+	startDate, _ := flags.GetString("start-date")
+	endDate, _ := flags.GetString("end-date")
+
+	var err error
+	if filter.StartDate, err = parseOptionalDate(startDate); err != nil {
+		return filter, err
+	}
+	if filter.EndDate, err = parseOptionalDate(endDate); err != nil {
+		return filter, err
+	}
+
+	float := func(name string) *float64 {
+		if !flags.Changed(name) {
+			return nil
+		}
+		v, _ := flags.GetFloat64(name)
+		return &v
+	}
+	price := func(name string) *decimal.Decimal {
+		if v := float(name); v != nil {
+			d := decimal.NewFromFloat(*v)
+			return &d
+		}
+		return nil
+	}
+	volume := func(name string) *int64 {
+		if !flags.Changed(name) {
+			return nil
+		}
+		v, _ := flags.GetInt64(name)
+		return &v
+	}
+
+	filter.MinPrice, filter.MaxPrice = price("min-price"), price("max-price")
+	filter.MinVolume, filter.MaxVolume = volume("min-volume"), volume("max-volume")
+	filter.MinVolatility, filter.MaxVolatility = float("min-volatility"), float("max-volatility")
+	filter.MinReturn, filter.MaxReturn = float("min-return"), float("max-return")
+	filter.MinRangePercent, filter.MaxRangePercent = float("min-range"), float("max-range")
+
+	filter.Days, _ = flags.GetInt("days")
+	filter.SortBy, _ = flags.GetString("sort")
+	filter.SortOrder, _ = flags.GetString("order")
+	filter.Limit, _ = flags.GetInt("limit")
+	filter.Cursor, _ = flags.GetString("cursor")
+
+	return filter, service.NormalizeScreenerFilter(&filter)
+}
+
+func runScreen(filter domain.AggregationFilter, asJSON bool) error {
+	ctx := context.Background()
+	cfg := config.Load()
+
+	pool, err := connectDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	analysisService := service.NewAnalysisService(pool, nil)
+
+	result, err := analysisService.Screen(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("erro ao executar screener: %w", err)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	if len(result.Data) == 0 {
+		fmt.Println("❌ Nenhum ticker atende aos filtros")
+		return nil
+	}
+
+	optional := func(v *float64) string {
+		if v == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%.2f", *v)
+	}
+
+	fmt.Printf("🔎 Screener: %d tickers (página %d)\n\n", result.TotalCount, result.Page)
+	fmt.Printf("%-8s %10s %10s %15s %9s %9s %9s %8s\n",
+		"Ticker", "Fech.", "Máxima", "Vol. médio", "Volat.%", "Ret.%", "Ampl.%", "Pregões")
+
+	for _, item := range result.Data {
+		lastClose, avgVolume := "-", "-"
+		if item.LastClose != nil {
+			lastClose = item.LastClose.StringFixed(2)
+		}
+		if item.AvgDailyVolume != nil {
+			avgVolume = formatNumber(*item.AvgDailyVolume)
+		}
+
+		fmt.Printf("%-8s %10s %10s %15s %9s %9s %9s %8d\n",
+			item.Ticker,
+			lastClose,
+			item.MaxRangeValue.StringFixed(2),
+			avgVolume,
+			optional(item.Volatility),
+			optional(item.ReturnPercent),
+			optional(item.PriceRangePercent),
+			item.Sessions)
+	}
+
+	if result.HasMore {
+		fmt.Printf("\nPróxima página: --cursor %s\n", result.NextCursor)
+	}
+
+	return nil
+}
+
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("data inválida: %w", err)
+	}
+
+	return &parsed, nil
+}
+
 func formatNumber(n int64) string {
 	if n == 0 {
 		return "0"
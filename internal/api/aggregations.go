@@ -0,0 +1,105 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+const maxBatchTickers = 500
+
+func (h *Handler) GetTickerAggregations(c *fiber.Ctx) error {
+	start := time.Now()
+
+	var req BatchAggregationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "corpo da requisição inválido")
+	}
+
+	var tickers []string
+	for _, ticker := range req.Tickers {
+		ticker = strings.ToUpper(strings.TrimSpace(ticker))
+		if ticker != "" && !containsTicker(tickers, ticker) {
+			tickers = append(tickers, ticker)
+		}
+	}
+
+	if len(tickers) == 0 || len(tickers) > maxBatchTickers {
+		return errorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("informe entre 1 e %d tickers", maxBatchTickers))
+	}
+
+	startDate, err := parseOptionalBodyDate(req.StartDate, "start_date")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	endDate, err := parseOptionalBodyDate(req.EndDate, "end_date")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
+		return errorResponse(c, fiber.StatusBadRequest, "end_date anterior a start_date")
+	}
+
+	results, err := h.aggregationService.GetTickerAggregations(c.Context(), tickers, startDate, endDate)
+	if err != nil {
+		logger.Error("erro ao buscar agregações em lote",
+			zap.Int("tickers", len(tickers)),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao buscar agregações")
+	}
+
+	response := BatchAggregationResponse{
+		Data:   []TickerAggregationResponse{},
+		Errors: []TickerAggregationError{},
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			message := "erro ao buscar agregação"
+			if errors.Is(result.Err, service.ErrNotFound) {
+				message = fmt.Sprintf("nenhum dado encontrado para o ticker %s", result.Ticker)
+			}
+			response.Errors = append(response.Errors, TickerAggregationError{Ticker: result.Ticker, Error: message})
+			continue
+		}
+
+		if result.CacheHit {
+			response.CacheHits++
+		}
+		metrics.RecordAggregationRequest(result.Ticker, result.CacheHit)
+
+		response.Data = append(response.Data, TickerAggregationResponse{
+			Ticker:         result.Aggregation.Ticker,
+			MaxRangeValue:  result.Aggregation.MaxRangeValue,
+			MaxDailyVolume: result.Aggregation.MaxDailyVolume,
+			CacheHit:       result.CacheHit,
+		})
+	}
+
+	response.ProcessingTime = time.Since(start).String()
+
+	return c.JSON(response)
+}
+
+func parseOptionalBodyDate(value, field string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := parseDate(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", field, err)
+	}
+
+	return &parsed, nil
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) CreateAlertRule(c *fiber.Ctx) error {
+	var req AlertRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "corpo da requisição inválido")
+	}
+
+	rule := domain.AlertRule{
+		Name:       strings.TrimSpace(req.Name),
+		Ticker:     strings.ToUpper(strings.TrimSpace(req.Ticker)),
+		Condition:  req.Condition,
+		Threshold:  req.Threshold,
+		Lookback:   req.Lookback,
+		WebhookURL: strings.TrimSpace(req.WebhookURL),
+	}
+
+	if rule.Lookback == 0 {
+		rule.Lookback = service.DefaultAlertLookback
+	}
+	if err := service.ValidateAlertRule(&rule); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	created, err := h.alertService.CreateRule(c.Context(), rule)
+	if err != nil {
+		logger.Error("erro ao criar regra de alerta", zap.Error(err))
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao criar regra de alerta")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(created)
+}
+
+func (h *Handler) ListAlertRules(c *fiber.Ctx) error {
+	rules, err := h.alertService.ListRules(c.Context(), c.QueryBool("active", false))
+	if err != nil {
+		logger.Error("erro ao listar regras de alerta", zap.Error(err))
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao listar regras de alerta")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  rules,
+		"count": len(rules),
+	})
+}
+
+func (h *Handler) DeleteAlertRule(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "id inválido")
+	}
+
+	err = h.alertService.DeleteRule(c.Context(), id)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "regra de alerta não encontrada")
+	}
+	if err != nil {
+		logger.Error("erro ao remover regra de alerta", zap.Int64("id", id), zap.Error(err))
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao remover regra de alerta")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) ListAlertEvents(c *fiber.Ctx) error {
+	filter := domain.AlertEventFilter{
+		Ticker:   strings.ToUpper(c.Query("ticker")),
+		Status:   c.Query("status"),
+		Page:     c.QueryInt("page", 1),
+		PageSize: c.QueryInt("page_size", 50),
+	}
+
+	if value := c.Query("rule_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "rule_id inválido")
+		}
+		filter.RuleID = &id
+	}
+
+	if filter.Page <= 0 || filter.PageSize <= 0 || filter.PageSize > 500 {
+		return errorResponse(c, fiber.StatusBadRequest, "page deve ser positivo e page_size deve estar entre 1 e 500")
+	}
+
+	events, total, err := h.alertService.ListEvents(c.Context(), filter)
+	if err != nil {
+		logger.Error("erro ao listar eventos de alerta", zap.Error(err))
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao listar eventos de alerta")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":        events,
+		"total_count": total,
+		"page":        filter.Page,
+		"page_size":   filter.PageSize,
+		"has_more":    (filter.Page-1)*filter.PageSize+len(events) < total,
+	})
+}
+
+// evaluateAlerts roda em segundo plano para que a entrega dos webhooks não
+// segure a resposta do refresh. Sem datas, avalia o último pregão.
+func (h *Handler) evaluateAlerts(dates ...time.Time) {
+	go func() {
+		if _, err := h.alertService.EvaluateDates(context.Background(), dates); err != nil && !errors.Is(err, service.ErrNotFound) {
+			logger.Error("erro ao avaliar alertas", zap.Error(err))
+		}
+	}()
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const maxBacktestTickers = 50
+
+func (h *Handler) RunBacktest(c *fiber.Ctx) error {
+	var req BacktestRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "corpo da requisição inválido")
+	}
+
+	var tickers []string
+	for _, ticker := range req.Tickers {
+		ticker = strings.ToUpper(strings.TrimSpace(ticker))
+		if ticker != "" && !containsTicker(tickers, ticker) {
+			tickers = append(tickers, ticker)
+		}
+	}
+
+	if len(tickers) == 0 || len(tickers) > maxBacktestTickers {
+		return errorResponse(c, fiber.StatusBadRequest, "informe entre 1 e 50 tickers")
+	}
+
+	startDate, err := parseDate(req.StartDate)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "start_date: "+err.Error())
+	}
+
+	endDate, err := parseDate(req.EndDate)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "end_date: "+err.Error())
+	}
+
+	if endDate.Before(startDate) {
+		return errorResponse(c, fiber.StatusBadRequest, "end_date anterior a start_date")
+	}
+
+	if err := req.Strategy.WithDefaults(len(tickers)).Validate(); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.analysisService.RunBacktest(c.Context(), tickers, startDate, endDate, req.Strategy)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		logger.Error("erro ao executar backtest",
+			zap.Strings("tickers", tickers),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao executar backtest")
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const maxCandleRangeDays = 31
+
+func (h *Handler) GetTickerCandles(c *fiber.Ctx) error {
+	ticker := c.Params("ticker")
+	interval := c.Query("interval", "5m")
+	fill := c.Query("fill")
+
+	if _, err := service.ParseCandleInterval(interval); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if fill != "" && fill != service.CandleFillNone && fill != service.CandleFillPrevious {
+		return errorResponse(c, fiber.StatusBadRequest, "fill inválido (use none ou previous)")
+	}
+
+	startDate, endDate, err := parseSessionRange(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if endDate.Sub(startDate) > maxCandleRangeDays*24*time.Hour {
+		return errorResponse(c, fiber.StatusBadRequest, "intervalo máximo de 31 dias")
+	}
+
+	candles, err := h.candleService.GetCandles(c.Context(), ticker, startDate, endDate, interval, fill)
+	if err != nil {
+		logger.Error("erro ao buscar candles",
+			zap.String("ticker", ticker),
+			zap.String("interval", interval),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao buscar candles")
+	}
+
+	return c.JSON(CandlesResponse{
+		Ticker:    ticker,
+		Interval:  interval,
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		Candles:   candles,
+		Count:     len(candles),
+	})
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) CompareTickerStats(c *fiber.Ctx) error {
+	ticker := strings.ToUpper(c.Params("ticker"))
+
+	var aStart, aEnd, bStart, bEnd, date *time.Time
+	for _, param := range []struct {
+		key    string
+		target **time.Time
+	}{
+		{"a_start", &aStart}, {"a_end", &aEnd}, {"b_start", &bStart}, {"b_end", &bEnd}, {"date", &date},
+	} {
+		value, err := parseDateQuery(c, param.key)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+		*param.target = value
+	}
+
+	a := c.Query("a")
+	if a == "" && aStart == nil && aEnd == nil {
+		a = service.PeriodMTD
+	}
+
+	var ref time.Time
+	if date != nil {
+		ref = *date
+	} else if aStart == nil {
+		latest, err := h.tradeService.LatestSession(c.Context())
+		if errors.Is(err, service.ErrNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "nenhum pregão carregado")
+		}
+		if err != nil {
+			logger.Error("erro ao buscar último pregão", zap.Error(err))
+			return errorResponse(c, fiber.StatusInternalServerError, "erro ao comparar períodos")
+		}
+		ref = latest
+	}
+
+	periodA, periodB, err := service.ResolveComparePeriods(ref, strings.ToLower(a), strings.ToLower(c.Query("b")), aStart, aEnd, bStart, bEnd)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.tradeService.CompareTickerStats(c.Context(), ticker, periodA, periodB)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "nenhum dado encontrado para o ticker "+ticker+" nos períodos")
+	}
+	if err != nil {
+		logger.Error("erro ao comparar períodos",
+			zap.String("ticker", ticker),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao comparar períodos")
+	}
+
+	return c.JSON(result)
+}
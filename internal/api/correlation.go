@@ -0,0 +1,86 @@
+package api
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const maxCorrelationTickers = 50
+
+func (h *Handler) GetCorrelation(c *fiber.Ctx) error {
+	var req CorrelationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "corpo da requisição inválido")
+	}
+
+	var tickers []string
+	for _, ticker := range req.Tickers {
+		ticker = strings.TrimSpace(ticker)
+		if ticker != "" && !containsTicker(tickers, ticker) {
+			tickers = append(tickers, ticker)
+		}
+	}
+
+	if len(tickers) < 2 || len(tickers) > maxCorrelationTickers {
+		return errorResponse(c, fiber.StatusBadRequest, "informe entre 2 e 50 tickers")
+	}
+
+	startDate, err := parseDate(req.StartDate)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "start_date: "+err.Error())
+	}
+
+	endDate, err := parseDate(req.EndDate)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "end_date: "+err.Error())
+	}
+
+	if endDate.Before(startDate) {
+		return errorResponse(c, fiber.StatusBadRequest, "end_date anterior a start_date")
+	}
+
+	if req.Frequency == "" {
+		req.Frequency = service.FrequencyDaily
+	}
+	if !service.ValidFrequency(req.Frequency) {
+		return errorResponse(c, fiber.StatusBadRequest, "frequency inválida (use daily, weekly ou monthly)")
+	}
+
+	query := domain.CorrelationQuery{
+		Tickers:   tickers,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Frequency: req.Frequency,
+		Spearman:  req.Spearman,
+		Benchmark: strings.TrimSpace(req.Benchmark),
+	}
+
+	result, err := h.analysisService.GetCorrelation(c.Context(), query)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		logger.Error("erro ao calcular correlação",
+			zap.Strings("tickers", tickers),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao calcular correlação")
+	}
+
+	return c.JSON(result)
+}
+
+func containsTicker(tickers []string, ticker string) bool {
+	for _, t := range tickers {
+		if t == ticker {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) GetTickerGaps(c *fiber.Ctx) error {
+	ticker := strings.ToUpper(c.Params("ticker"))
+
+	minPercent, err := parseMinPercent(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	startDate, err := parseDateQuery(c, "start")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	endDate, err := parseDateQuery(c, "end")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if endDate == nil {
+		latest, err := h.tradeService.LatestSession(c.Context())
+		if errors.Is(err, service.ErrNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "nenhum pregão carregado")
+		}
+		if err != nil {
+			logger.Error("erro ao buscar último pregão", zap.Error(err))
+			return errorResponse(c, fiber.StatusInternalServerError, "erro ao calcular gaps")
+		}
+		endDate = &latest
+	}
+	if startDate == nil {
+		start := endDate.AddDate(-1, 0, 0)
+		startDate = &start
+	}
+
+	if endDate.Before(*startDate) {
+		return errorResponse(c, fiber.StatusBadRequest, "end anterior a start")
+	}
+
+	result, err := h.analysisService.GetTickerGaps(c.Context(), ticker, *startDate, *endDate, minPercent)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "nenhum dado encontrado para o ticker "+ticker+" no período")
+	}
+	if err != nil {
+		logger.Error("erro ao calcular gaps",
+			zap.String("ticker", ticker),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao calcular gaps")
+	}
+
+	return c.JSON(result)
+}
+
+func (h *Handler) GetGapRanking(c *fiber.Ctx) error {
+	date, err := parseDateQuery(c, "date")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if date == nil {
+		latest, err := h.tradeService.LatestSession(c.Context())
+		if errors.Is(err, service.ErrNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "nenhum pregão carregado")
+		}
+		if err != nil {
+			logger.Error("erro ao buscar último pregão", zap.Error(err))
+			return errorResponse(c, fiber.StatusInternalServerError, "erro ao calcular ranking de gaps")
+		}
+		date = &latest
+	}
+
+	minPercent, err := parseMinPercent(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	direction := strings.ToLower(c.Query("direction"))
+	if direction != "" && direction != service.GapDirectionUp && direction != service.GapDirectionDown {
+		return errorResponse(c, fiber.StatusBadRequest, "direction inválida (use up ou down)")
+	}
+
+	limit := c.QueryInt("limit", 20)
+	if limit <= 0 || limit > 100 {
+		return errorResponse(c, fiber.StatusBadRequest, "limit deve estar entre 1 e 100")
+	}
+
+	result, err := h.analysisService.GetGapRanking(c.Context(), *date, minPercent, direction, limit)
+	if err != nil {
+		logger.Error("erro ao calcular ranking de gaps",
+			zap.Time("date", *date),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao calcular ranking de gaps")
+	}
+
+	return c.JSON(result)
+}
+
+func parseMinPercent(c *fiber.Ctx) (float64, error) {
+	value := c.Query("min_pct")
+	if value == "" {
+		return 0, nil
+	}
+
+	minPercent, err := strconv.ParseFloat(value, 64)
+	if err != nil || minPercent < 0 {
+		return 0, errors.New("min_pct deve ser um número não negativo")
+	}
+	return minPercent, nil
+}
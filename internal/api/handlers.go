@@ -2,43 +2,67 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/config"
+	"github.com/jeovahfialho/b3-analyzer/internal/ingestion"
 	"github.com/jeovahfialho/b3-analyzer/internal/service"
 	"github.com/jeovahfialho/b3-analyzer/internal/storage/cache"
 	"github.com/jeovahfialho/b3-analyzer/internal/storage/postgres"
 	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
 	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"github.com/jeovahfialho/b3-analyzer/pkg/stats"
 	"go.uber.org/zap"
 )
 
 type Handler struct {
-	db                 *postgres.DB
-	cacheService       *cache.RedisCache
-	aggregationService *service.AggregationService
-	tradeService       *service.TradeService
-	analysisService    *service.AnalysisService
-	ingestionService   *service.IngestionService
+	cfg                   *config.Config
+	db                    *postgres.DB
+	cacheService          *cache.RedisCache
+	aggregationService    *service.AggregationService
+	tradeService          *service.TradeService
+	analysisService       *service.AnalysisService
+	ingestionService      *service.IngestionService
+	reconciliationService *service.ReconciliationService
+	candleService         *service.CandleService
+	alertService          *service.AlertService
+	portfolioService      *service.PortfolioService
+	indexService          *service.IndexService
 }
 
 func NewHandler(
+	cfg *config.Config,
 	db *postgres.DB,
 	cacheService *cache.RedisCache,
 	aggregationService *service.AggregationService,
 	tradeService *service.TradeService,
 	analysisService *service.AnalysisService,
 	ingestionService *service.IngestionService,
+	reconciliationService *service.ReconciliationService,
+	candleService *service.CandleService,
+	alertService *service.AlertService,
+	portfolioService *service.PortfolioService,
+	indexService *service.IndexService,
 ) *Handler {
 	return &Handler{
-		db:                 db,
-		cacheService:       cacheService,
-		aggregationService: aggregationService,
-		tradeService:       tradeService,
-		analysisService:    analysisService,
-		ingestionService:   ingestionService,
+		cfg:                   cfg,
+		db:                    db,
+		cacheService:          cacheService,
+		aggregationService:    aggregationService,
+		tradeService:          tradeService,
+		analysisService:       analysisService,
+		ingestionService:      ingestionService,
+		reconciliationService: reconciliationService,
+		candleService:         candleService,
+		alertService:          alertService,
+		portfolioService:      portfolioService,
+		indexService:          indexService,
 	}
 }
 
@@ -205,7 +229,15 @@ func (h *Handler) GetTickerHistory(c *fiber.Ctx) error {
 		endDate = &parsed
 	}
 
-	history, err := h.tradeService.GetTickerHistory(c.Context(), ticker, startDate, endDate)
+	granularity := strings.ToLower(c.Query("granularity", service.GranularityDay))
+	if !service.ValidGranularity(granularity) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: "granularity inválida (use day, week, month, quarter ou year)",
+			Code:  fiber.StatusBadRequest,
+		})
+	}
+
+	history, err := h.tradeService.GetTickerHistoryByPeriod(c.Context(), ticker, granularity, startDate, endDate)
 	if err != nil {
 		logger.Error("erro ao buscar histórico",
 			zap.String("ticker", ticker),
@@ -218,9 +250,10 @@ func (h *Handler) GetTickerHistory(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(fiber.Map{
-		"ticker":  ticker,
-		"history": history,
-		"count":   len(history),
+		"ticker":      ticker,
+		"granularity": granularity,
+		"history":     history,
+		"count":       len(history),
 	})
 }
 
@@ -229,6 +262,9 @@ func (h *Handler) GetTickerStats(c *fiber.Ctx) error {
 	days := c.QueryInt("days", 30)
 
 	stats, err := h.tradeService.GetTickerStats(c.Context(), ticker, days)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "nenhum dado encontrado para o ticker "+ticker)
+	}
 	if err != nil {
 		logger.Error("erro ao buscar estatísticas",
 			zap.String("ticker", ticker),
@@ -243,26 +279,36 @@ func (h *Handler) GetTickerStats(c *fiber.Ctx) error {
 	return c.JSON(stats)
 }
 
+// RefreshViews reconstrói daily_aggregations em segundo plano: as cargas já
+// atualizam os pregões tocados, e a reconstrução completa passa fácil do
+// APIWriteTimeout.
 func (h *Handler) RefreshViews(c *fiber.Ctx) error {
-	ctx := c.Context()
+	jobID := generateJobID()
 
-	start := time.Now()
-	if err := h.aggregationService.RefreshMaterializedViews(ctx); err != nil {
-		logger.Error("erro ao atualizar views", zap.Error(err))
-		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
-			Error:     "erro ao atualizar views",
-			Code:      fiber.StatusInternalServerError,
-			RequestID: getRequestID(c),
-			Timestamp: time.Now(),
-		})
-	}
+	go func() {
+		start := time.Now()
+		rows, err := h.aggregationService.RebuildDailyAggregations(context.Background())
+		if err != nil {
+			logger.Error("erro ao reconstruir agregações",
+				zap.String("job_id", jobID),
+				zap.Error(err))
+			return
+		}
 
-	duration := time.Since(start)
+		logger.Info("agregações reconstruídas",
+			zap.String("job_id", jobID),
+			zap.Int64("rows", rows),
+			zap.Duration("duration", time.Since(start)))
 
-	return c.JSON(fiber.Map{
-		"status":   "success",
-		"message":  "views atualizadas com sucesso",
-		"duration": duration.String(),
+		h.updateIndexes()
+		h.evaluateAlerts()
+		h.warmIntradayProfiles()
+	}()
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"job_id":  jobID,
+		"status":  "processing",
+		"message": "reconstrução das agregações iniciada",
 	})
 }
 
@@ -319,24 +365,34 @@ func (h *Handler) LoadDataFromFile(c *fiber.Ctx) error {
 		})
 	}
 
+	filePath, err := dataFilePath(h.cfg.DataDir, req.FilePath)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
 	if req.Async {
 
 		jobID := generateJobID()
 
 		go func() {
 			ctx := context.Background()
-			result, err := h.ingestionService.ProcessFile(ctx, req.FilePath)
+			result, err := h.ingestionService.ProcessFile(ctx, filePath)
 
 			if err != nil {
 				logger.Error("erro ao processar arquivo",
-					zap.String("file", req.FilePath),
+					zap.String("file", filePath),
 					zap.String("job_id", jobID),
 					zap.Error(err))
 			} else {
 				logger.Info("arquivo processado com sucesso",
-					zap.String("file", req.FilePath),
+					zap.String("file", filePath),
 					zap.String("job_id", jobID),
 					zap.Int64("records", result.RecordsCount))
+
+				h.aggregationService.InvalidateCache(ctx)
+				h.updateIndexes()
+				h.evaluateAlerts(result.Dates...)
+				h.warmIntradayProfiles()
 			}
 		}()
 
@@ -347,7 +403,7 @@ func (h *Handler) LoadDataFromFile(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.ingestionService.ProcessFile(c.Context(), req.FilePath)
+	result, err := h.ingestionService.ProcessFile(c.Context(), filePath)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
 			Error: "erro ao processar arquivo",
@@ -355,6 +411,11 @@ func (h *Handler) LoadDataFromFile(c *fiber.Ctx) error {
 		})
 	}
 
+	h.aggregationService.InvalidateCache(c.Context())
+	h.updateIndexes()
+	h.evaluateAlerts(result.Dates...)
+	h.warmIntradayProfiles()
+
 	return c.JSON(LoadDataResponse{
 		RecordsCount: result.RecordsCount,
 		Status:       "completed",
@@ -362,10 +423,76 @@ func (h *Handler) LoadDataFromFile(c *fiber.Ctx) error {
 	})
 }
 
+func (h *Handler) VerifyLoad(c *fiber.Ctx) error {
+	var req VerifyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error:     "corpo da requisição inválido",
+			Code:      fiber.StatusBadRequest,
+			RequestID: getRequestID(c),
+			Timestamp: time.Now(),
+		})
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error:     "formato de data inválido (use YYYY-MM-DD)",
+			Code:      fiber.StatusBadRequest,
+			RequestID: getRequestID(c),
+			Timestamp: time.Now(),
+		})
+	}
+
+	filePath := req.FilePath
+	if filePath != "" {
+		filePath, err = dataFilePath(h.cfg.DataDir, filePath)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+	} else {
+		filePath, err = ingestion.FindSourceFile(h.cfg.DataDir, date)
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+				Error:     err.Error(),
+				Code:      fiber.StatusNotFound,
+				RequestID: getRequestID(c),
+				Timestamp: time.Now(),
+			})
+		}
+	}
+
+	report, err := h.reconciliationService.Reconcile(c.Context(), filePath, date)
+	if err != nil {
+		logger.Error("erro na reconciliação",
+			zap.String("file", filePath),
+			zap.Error(err))
+
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Error:     "erro na reconciliação",
+			Code:      fiber.StatusInternalServerError,
+			RequestID: getRequestID(c),
+			Timestamp: time.Now(),
+		})
+	}
+
+	// 409 permite que o agendador trate divergências como falha
+	if !report.Consistent {
+		return c.Status(fiber.StatusConflict).JSON(report)
+	}
+
+	return c.JSON(report)
+}
+
 func (h *Handler) GetTopVolume(c *fiber.Ctx) error {
 	limit := c.QueryInt("limit", 10)
+	days := c.QueryInt("days", 30)
+
+	if limit <= 0 || limit > 500 || days <= 0 {
+		return errorResponse(c, fiber.StatusBadRequest, "limit deve estar entre 1 e 500 e days deve ser positivo")
+	}
 
-	result, err := h.analysisService.GetTopVolumeTickets(c.Context(), limit)
+	result, err := h.analysisService.GetTopVolumeTickets(c.Context(), limit, days)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
 			Error: "erro ao buscar top volume",
@@ -390,7 +517,14 @@ func (h *Handler) GetPriceRange(c *fiber.Ctx) error {
 		})
 	}
 
+	if days <= 0 {
+		return errorResponse(c, fiber.StatusBadRequest, "days deve ser positivo")
+	}
+
 	result, err := h.analysisService.GetPriceRange(c.Context(), ticker, days)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, fmt.Sprintf("nenhum dado encontrado para o ticker %s", ticker))
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
 			Error: "erro ao buscar range de preços",
@@ -412,7 +546,20 @@ func (h *Handler) GetVolatility(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.analysisService.GetVolatility(c.Context(), ticker, days)
+	method := c.Query("method", stats.VolatilityCloseToClose)
+
+	if !stats.ValidVolatilityMethod(method) {
+		return errorResponse(c, fiber.StatusBadRequest, "method inválido (use close, parkinson ou garman_klass)")
+	}
+
+	if days < 2 {
+		return errorResponse(c, fiber.StatusBadRequest, "days deve ser maior que 1")
+	}
+
+	result, err := h.analysisService.GetVolatility(c.Context(), ticker, days, method)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, fmt.Sprintf("pregões insuficientes para o ticker %s", ticker))
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
 			Error: "erro ao calcular volatilidade",
@@ -427,6 +574,101 @@ func generateJobID() string {
 	return fmt.Sprintf("job_%d_%s", time.Now().Unix(), randomString(8))
 }
 
+// dataFilePath limpa o caminho recebido e recusa os que saem de dataDir,
+// para que as rotas de carga não leiam arquivos arbitrários do servidor.
+func dataFilePath(dataDir, path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("file_path é obrigatório")
+	}
+
+	root, err := filepath.Abs(dataDir)
+	if err != nil {
+		return "", fmt.Errorf("diretório de dados inválido: %w", err)
+	}
+
+	abs, err := filepath.Abs(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("caminho inválido: %s", path)
+	}
+
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("arquivo fora do diretório de dados: %s", path)
+	}
+
+	return abs, nil
+}
+
+func errorResponse(c *fiber.Ctx, status int, message string) error {
+	return c.Status(status).JSON(ErrorResponse{
+		Error:     message,
+		Code:      status,
+		RequestID: getRequestID(c),
+		Timestamp: time.Now(),
+	})
+}
+
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("data é obrigatória (use YYYY-MM-DD)")
+	}
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("formato de data inválido (use YYYY-MM-DD)")
+	}
+
+	return parsed, nil
+}
+
+func parseDateQuery(c *fiber.Ctx, key string) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("formato de data inválido em %s (use YYYY-MM-DD)", key)
+	}
+
+	return &parsed, nil
+}
+
+// parseSessionRange aceita um único pregão (date) ou um intervalo
+// (start_date/end_date); end_date assume start_date quando omitido.
+func parseSessionRange(c *fiber.Ctx) (time.Time, time.Time, error) {
+	date, err := parseDateQuery(c, "date")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if date != nil {
+		return *date, *date, nil
+	}
+
+	startDate, err := parseDateQuery(c, "start_date")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if startDate == nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("informe date ou start_date")
+	}
+
+	endDate, err := parseDateQuery(c, "end_date")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if endDate == nil {
+		endDate = startDate
+	}
+
+	if endDate.Before(*startDate) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end_date anterior a start_date")
+	}
+
+	return *startDate, *endDate, nil
+}
+
 func getRequestID(c *fiber.Ctx) string {
 	if id := c.Locals("requestID"); id != nil {
 		return id.(string)
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) CreateIndex(c *fiber.Ctx) error {
+	var req IndexRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "corpo da requisição inválido")
+	}
+
+	baseDate, err := parseDate(req.BaseDate)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "base_date: "+err.Error())
+	}
+
+	index := domain.CustomIndex{
+		Code:         strings.ToUpper(strings.TrimSpace(req.Code)),
+		Name:         strings.TrimSpace(req.Name),
+		Weighting:    strings.ToLower(req.Weighting),
+		Rebalance:    strings.ToLower(req.Rebalance),
+		BaseDate:     baseDate,
+		BaseValue:    req.BaseValue,
+		Constituents: req.Constituents,
+	}
+	for i := range index.Constituents {
+		index.Constituents[i].Ticker = strings.ToUpper(strings.TrimSpace(index.Constituents[i].Ticker))
+	}
+	service.NormalizeIndex(&index)
+	if err := service.ValidateIndex(index); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	created, err := h.indexService.CreateIndex(c.Context(), index)
+	if errors.Is(err, service.ErrIndexExists) {
+		return errorResponse(c, fiber.StatusConflict, "já existe um índice com esse code")
+	}
+	if err != nil {
+		logger.Error("erro ao criar índice", zap.Error(err))
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao criar índice")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(created)
+}
+
+func (h *Handler) ListIndexes(c *fiber.Ctx) error {
+	indexes, err := h.indexService.ListIndexes(c.Context())
+	if err != nil {
+		logger.Error("erro ao listar índices", zap.Error(err))
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao listar índices")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  indexes,
+		"count": len(indexes),
+	})
+}
+
+func (h *Handler) GetIndex(c *fiber.Ctx) error {
+	index, err := h.indexService.GetIndex(c.Context(), indexCode(c))
+	if err != nil {
+		return indexError(c, err, "erro ao buscar índice")
+	}
+
+	return c.JSON(index)
+}
+
+func (h *Handler) DeleteIndex(c *fiber.Ctx) error {
+	if err := h.indexService.DeleteIndex(c.Context(), indexCode(c)); err != nil {
+		return indexError(c, err, "erro ao remover índice")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) GetIndexLevels(c *fiber.Ctx) error {
+	code := indexCode(c)
+
+	startDate, err := parseDateQuery(c, "start_date")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	endDate, err := parseDateQuery(c, "end_date")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	levels, err := h.indexService.GetLevels(c.Context(), code, startDate, endDate)
+	if err != nil {
+		return indexError(c, err, "erro ao buscar níveis do índice")
+	}
+
+	return c.JSON(fiber.Map{
+		"code":   code,
+		"ticker": service.IndexTickerPrefix + code,
+		"levels": levels,
+		"count":  len(levels),
+	})
+}
+
+// updateIndexes roda em segundo plano após cargas e refresh.
+func (h *Handler) updateIndexes() {
+	go func() {
+		if _, err := h.indexService.UpdateIndexes(context.Background()); err != nil {
+			logger.Error("erro ao atualizar índices", zap.Error(err))
+		}
+	}()
+}
+
+// indexCode aceita o code com ou sem o prefixo IDX:.
+func indexCode(c *fiber.Ctx) string {
+	return strings.TrimPrefix(strings.ToUpper(c.Params("code")), service.IndexTickerPrefix)
+}
+
+func indexError(c *fiber.Ctx, err error, message string) error {
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "índice não encontrado")
+	}
+
+	logger.Error(message, zap.Error(err))
+	return errorResponse(c, fiber.StatusInternalServerError, message)
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/indicators"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) GetTickerIndicators(c *fiber.Ctx) error {
+	ticker := c.Params("ticker")
+
+	specs, err := indicators.ParseSpecs(c.Query("names"))
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	startDate, err := parseDateQuery(c, "start_date")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	endDate, err := parseDateQuery(c, "end_date")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	series, err := h.analysisService.GetIndicators(c.Context(), ticker, specs, startDate, endDate)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, fmt.Sprintf("nenhum dado encontrado para o ticker %s", ticker))
+	}
+	if err != nil {
+		logger.Error("erro ao calcular indicadores",
+			zap.String("ticker", ticker),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao calcular indicadores")
+	}
+
+	return c.JSON(series)
+}
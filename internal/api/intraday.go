@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) GetIntradayProfile(c *fiber.Ctx) error {
+	return h.respondIntradayProfile(c, strings.ToUpper(c.Params("ticker")))
+}
+
+func (h *Handler) GetMarketIntradayProfile(c *fiber.Ctx) error {
+	return h.respondIntradayProfile(c, "")
+}
+
+func (h *Handler) respondIntradayProfile(c *fiber.Ctx, ticker string) error {
+	days := c.QueryInt("days", service.IntradayDefaultDays)
+	if days <= 0 || days > 250 {
+		return errorResponse(c, fiber.StatusBadRequest, "days deve estar entre 1 e 250")
+	}
+
+	bucket := c.Query("bucket", service.IntradayDefaultBucket)
+	if _, err := service.ParseCandleInterval(bucket); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	profile, err := h.candleService.GetIntradayProfile(c.Context(), ticker, days, bucket)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "nenhum negócio encontrado no período")
+	}
+	if err != nil {
+		logger.Error("erro ao buscar perfil intradiário",
+			zap.String("ticker", ticker),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao buscar perfil intradiário")
+	}
+
+	return c.JSON(profile)
+}
+
+// warmIntradayProfiles roda em segundo plano após cargas e refresh.
+func (h *Handler) warmIntradayProfiles() {
+	go func() {
+		if err := h.candleService.WarmIntradayProfiles(context.Background()); err != nil && !errors.Is(err, service.ErrNotFound) {
+			logger.Error("erro ao atualizar perfis intradiários", zap.Error(err))
+		}
+	}()
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) GetTickerLiquidity(c *fiber.Ctx) error {
+	ticker := strings.ToUpper(c.Params("ticker"))
+
+	startDate, err := parseDateQuery(c, "start_date")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	endDate, err := parseDateQuery(c, "end_date")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if endDate == nil {
+		latest, err := h.tradeService.LatestSession(c.Context())
+		if errors.Is(err, service.ErrNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "nenhum pregão carregado")
+		}
+		if err != nil {
+			logger.Error("erro ao buscar último pregão", zap.Error(err))
+			return errorResponse(c, fiber.StatusInternalServerError, "erro ao calcular liquidez")
+		}
+		endDate = &latest
+	}
+	if startDate == nil {
+		start := endDate.AddDate(0, -1, 0)
+		startDate = &start
+	}
+
+	if endDate.Before(*startDate) {
+		return errorResponse(c, fiber.StatusBadRequest, "end_date anterior a start_date")
+	}
+
+	result, err := h.analysisService.GetTickerLiquidity(c.Context(), ticker, *startDate, *endDate)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "nenhum dado encontrado para o ticker "+ticker+" no período")
+	}
+	if err != nil {
+		logger.Error("erro ao calcular liquidez",
+			zap.String("ticker", ticker),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao calcular liquidez")
+	}
+
+	return c.JSON(fiber.Map{
+		"ticker":     ticker,
+		"start_date": startDate.Format("2006-01-02"),
+		"end_date":   endDate.Format("2006-01-02"),
+		"data":       result,
+	})
+}
+
+func (h *Handler) GetLiquidityRanking(c *fiber.Ctx) error {
+	date, err := parseDateQuery(c, "date")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if date == nil {
+		latest, err := h.tradeService.LatestSession(c.Context())
+		if errors.Is(err, service.ErrNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "nenhum pregão carregado")
+		}
+		if err != nil {
+			logger.Error("erro ao buscar último pregão", zap.Error(err))
+			return errorResponse(c, fiber.StatusInternalServerError, "erro ao calcular ranking de liquidez")
+		}
+		date = &latest
+	}
+
+	sortBy := c.Query("sort", "amihud")
+	if !service.ValidLiquiditySort(sortBy) {
+		return errorResponse(c, fiber.StatusBadRequest,
+			"sort inválido (use amihud, roll_spread_bps, median_inter_trade_seconds, trades_per_minute, avg_trade_size ou financial_volume)")
+	}
+
+	page := c.QueryInt("page", 1)
+	pageSize := c.QueryInt("page_size", 50)
+	if page <= 0 || pageSize <= 0 || pageSize > 500 {
+		return errorResponse(c, fiber.StatusBadRequest, "page deve ser positivo e page_size deve estar entre 1 e 500")
+	}
+
+	result, err := h.analysisService.GetLiquidityRanking(c.Context(), *date, sortBy, page, pageSize)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "nenhum dado encontrado para "+date.Format("2006-01-02"))
+	}
+	if err != nil {
+		logger.Error("erro ao calcular ranking de liquidez",
+			zap.Time("date", *date),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao calcular ranking de liquidez")
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) GetMarketOverview(c *fiber.Ctx) error {
+	date, err := parseDateQuery(c, "date")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	minFinancialVolume, err := decimal.NewFromString(c.Query("min_financial_volume", "1000000"))
+	if err != nil || minFinancialVolume.IsNegative() {
+		return errorResponse(c, fiber.StatusBadRequest, "min_financial_volume inválido")
+	}
+
+	opts := service.MarketOverviewOptions{
+		MinFinancialVolume: minFinancialVolume,
+		MinTrades:          c.QueryInt("min_trades", 100),
+		Limit:              c.QueryInt("limit", 5),
+	}
+
+	if opts.MinTrades < 0 || opts.Limit <= 0 || opts.Limit > 50 {
+		return errorResponse(c, fiber.StatusBadRequest, "min_trades não pode ser negativo e limit deve estar entre 1 e 50")
+	}
+
+	if date == nil {
+		latest, err := h.tradeService.LatestSession(c.Context())
+		if errors.Is(err, service.ErrNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "nenhum pregão carregado")
+		}
+		if err != nil {
+			logger.Error("erro ao buscar último pregão", zap.Error(err))
+			return errorResponse(c, fiber.StatusInternalServerError, "erro ao buscar visão geral do mercado")
+		}
+		date = &latest
+	}
+
+	overview, err := h.tradeService.GetMarketOverview(c.Context(), *date, opts)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "nenhum dado encontrado para "+date.Format("2006-01-02"))
+	}
+	if err != nil {
+		logger.Error("erro ao buscar visão geral do mercado",
+			zap.Time("date", *date),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao buscar visão geral do mercado")
+	}
+
+	return c.JSON(overview)
+}
@@ -0,0 +1,240 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) CreatePortfolio(c *fiber.Ctx) error {
+	var req PortfolioRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "corpo da requisição inválido")
+	}
+
+	portfolio := domain.Portfolio{
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+	}
+	if err := service.ValidatePortfolioName(portfolio.Name); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	for i, item := range req.Positions {
+		position, err := toPosition(item)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "posição "+strconv.Itoa(i)+": "+err.Error())
+		}
+		portfolio.Positions = append(portfolio.Positions, position)
+	}
+
+	created, err := h.portfolioService.CreatePortfolio(c.Context(), portfolio)
+	if err != nil {
+		logger.Error("erro ao criar carteira", zap.Error(err))
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao criar carteira")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(created)
+}
+
+func (h *Handler) ListPortfolios(c *fiber.Ctx) error {
+	portfolios, err := h.portfolioService.ListPortfolios(c.Context())
+	if err != nil {
+		logger.Error("erro ao listar carteiras", zap.Error(err))
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao listar carteiras")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  portfolios,
+		"count": len(portfolios),
+	})
+}
+
+func (h *Handler) GetPortfolio(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	portfolio, err := h.portfolioService.GetPortfolio(c.Context(), id)
+	if err != nil {
+		return portfolioError(c, err, "erro ao buscar carteira")
+	}
+
+	return c.JSON(portfolio)
+}
+
+func (h *Handler) UpdatePortfolio(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	var req PortfolioRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "corpo da requisição inválido")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if err := service.ValidatePortfolioName(name); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := h.portfolioService.UpdatePortfolio(c.Context(), id, name, strings.TrimSpace(req.Description)); err != nil {
+		return portfolioError(c, err, "erro ao atualizar carteira")
+	}
+
+	return h.GetPortfolio(c)
+}
+
+func (h *Handler) DeletePortfolio(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := h.portfolioService.DeletePortfolio(c.Context(), id); err != nil {
+		return portfolioError(c, err, "erro ao remover carteira")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) AddPosition(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	var req PositionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "corpo da requisição inválido")
+	}
+
+	position, err := toPosition(req)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	created, err := h.portfolioService.AddPosition(c.Context(), id, position)
+	if err != nil {
+		return portfolioError(c, err, "erro ao criar posição")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(created)
+}
+
+func (h *Handler) UpdatePosition(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	positionID, err := paramID(c, "positionId")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	var req PositionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "corpo da requisição inválido")
+	}
+
+	position, err := toPosition(req)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	position.ID = positionID
+
+	if err := h.portfolioService.UpdatePosition(c.Context(), id, position); err != nil {
+		return portfolioError(c, err, "erro ao atualizar posição")
+	}
+
+	position.PortfolioID = id
+	return c.JSON(position)
+}
+
+func (h *Handler) DeletePosition(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	positionID, err := paramID(c, "positionId")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := h.portfolioService.DeletePosition(c.Context(), id, positionID); err != nil {
+		return portfolioError(c, err, "erro ao remover posição")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) GetPortfolioValuation(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	date, err := parseDateQuery(c, "date")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	startDate, err := parseDateQuery(c, "start_date")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if date != nil && startDate != nil && date.Before(*startDate) {
+		return errorResponse(c, fiber.StatusBadRequest, "date anterior a start_date")
+	}
+
+	valuation, err := h.portfolioService.GetValuation(c.Context(), id, date, startDate)
+	if err != nil {
+		return portfolioError(c, err, "erro ao calcular valorização da carteira")
+	}
+
+	return c.JSON(valuation)
+}
+
+func toPosition(req PositionRequest) (domain.Position, error) {
+	tradeDate, err := parseDate(req.TradeDate)
+	if err != nil {
+		return domain.Position{}, err
+	}
+
+	position := domain.Position{
+		Ticker:    strings.ToUpper(strings.TrimSpace(req.Ticker)),
+		Quantity:  req.Quantity,
+		CostBasis: req.CostBasis,
+		TradeDate: tradeDate,
+	}
+
+	return position, service.ValidatePosition(position)
+}
+
+func portfolioError(c *fiber.Ctx, err error, message string) error {
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "carteira ou posição não encontrada")
+	}
+
+	logger.Error(message, zap.Error(err))
+	return errorResponse(c, fiber.StatusInternalServerError, message)
+}
+
+func paramID(c *fiber.Ctx, name string) (int64, error) {
+	id, err := strconv.ParseInt(c.Params(name), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New(name + " inválido")
+	}
+	return id, nil
+}
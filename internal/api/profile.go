@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) GetVolumeProfile(c *fiber.Ctx) error {
+	ticker := strings.ToUpper(c.Params("ticker"))
+
+	startDate, err := parseDateQuery(c, "start")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	endDate, err := parseDateQuery(c, "end")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if endDate == nil {
+		latest, err := h.tradeService.LatestSession(c.Context())
+		if errors.Is(err, service.ErrNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "nenhum pregão carregado")
+		}
+		if err != nil {
+			logger.Error("erro ao buscar último pregão", zap.Error(err))
+			return errorResponse(c, fiber.StatusInternalServerError, "erro ao buscar perfil de volume")
+		}
+		endDate = &latest
+	}
+	if startDate == nil {
+		startDate = endDate
+	}
+
+	if endDate.Before(*startDate) {
+		return errorResponse(c, fiber.StatusBadRequest, "end anterior a start")
+	}
+
+	bins := c.QueryInt("bins", 50)
+	if bins <= 0 || bins > 500 {
+		return errorResponse(c, fiber.StatusBadRequest, "bins deve estar entre 1 e 500")
+	}
+
+	mode := c.Query("mode", service.ProfileModeTick)
+	if mode != service.ProfileModeTick && mode != service.ProfileModeFixed {
+		return errorResponse(c, fiber.StatusBadRequest, "mode inválido (use tick ou fixed)")
+	}
+
+	tickSize := service.DefaultTickSize
+	if value := c.Query("tick_size"); value != "" {
+		tickSize, err = decimal.NewFromString(value)
+		if err != nil || !tickSize.IsPositive() {
+			return errorResponse(c, fiber.StatusBadRequest, "tick_size deve ser positivo")
+		}
+	}
+
+	profile, err := h.candleService.GetVolumeProfile(c.Context(), ticker, *startDate, *endDate, bins, mode, tickSize)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "nenhum negócio encontrado para o ticker "+ticker+" no período")
+	}
+	if err != nil {
+		logger.Error("erro ao buscar perfil de volume",
+			zap.String("ticker", ticker),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao buscar perfil de volume")
+	}
+
+	return c.JSON(profile)
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) GetVolumeRanking(c *fiber.Ctx) error {
+	q := domain.VolumeRankingQuery{
+		Window:            c.QueryInt("window", 5),
+		Compare:           c.QueryInt("compare", 20),
+		Metric:            c.Query("metric", service.VolumeMetricShares),
+		Prefix:            strings.ToUpper(strings.TrimSpace(c.Query("prefix"))),
+		ExcludeFractional: c.QueryBool("exclude_fractional", false),
+		Page:              c.QueryInt("page", 1),
+		PageSize:          c.QueryInt("page_size", 50),
+	}
+
+	if !service.ValidVolumeMetric(q.Metric) {
+		return errorResponse(c, fiber.StatusBadRequest, "metric inválida (use shares ou financial)")
+	}
+
+	if q.Window <= 0 || q.Compare < 0 {
+		return errorResponse(c, fiber.StatusBadRequest, "window deve ser positivo e compare não pode ser negativo")
+	}
+
+	if q.Page <= 0 || q.PageSize <= 0 || q.PageSize > 500 {
+		return errorResponse(c, fiber.StatusBadRequest, "page deve ser positivo e page_size deve estar entre 1 e 500")
+	}
+
+	for _, ticker := range strings.Split(c.Query("tickers"), ",") {
+		ticker = strings.ToUpper(strings.TrimSpace(ticker))
+		if ticker != "" && !containsTicker(q.Tickers, ticker) {
+			q.Tickers = append(q.Tickers, ticker)
+		}
+	}
+
+	result, err := h.analysisService.GetVolumeRanking(c.Context(), q)
+	if err != nil {
+		logger.Error("erro ao buscar ranking de volume",
+			zap.String("metric", q.Metric),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao buscar ranking de volume")
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) GetRealizedVolatility(c *fiber.Ctx) error {
+	ticker := strings.ToUpper(c.Params("ticker"))
+
+	startDate, err := parseDateQuery(c, "start")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	endDate, err := parseDateQuery(c, "end")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if endDate == nil {
+		latest, err := h.tradeService.LatestSession(c.Context())
+		if errors.Is(err, service.ErrNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "nenhum pregão carregado")
+		}
+		if err != nil {
+			logger.Error("erro ao buscar último pregão", zap.Error(err))
+			return errorResponse(c, fiber.StatusInternalServerError, "erro ao buscar volatilidade realizada")
+		}
+		endDate = &latest
+	}
+	if startDate == nil {
+		// um ano de pregões por padrão, o suficiente para os cones
+		start := endDate.AddDate(-1, 0, 0)
+		startDate = &start
+	}
+
+	if endDate.Before(*startDate) {
+		return errorResponse(c, fiber.StatusBadRequest, "end anterior a start")
+	}
+
+	frequency := c.Query("freq", "5m")
+	if _, err := service.ParseCandleInterval(frequency); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	horizons := service.DefaultConeHorizons
+	if value := c.Query("horizons"); value != "" {
+		horizons = nil
+		for _, part := range strings.Split(value, ",") {
+			horizon, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || horizon <= 0 {
+				return errorResponse(c, fiber.StatusBadRequest, "horizons deve ser uma lista de inteiros positivos")
+			}
+			horizons = append(horizons, horizon)
+		}
+	}
+
+	result, err := h.analysisService.GetRealizedVolatility(c.Context(), ticker, *startDate, *endDate, frequency, horizons)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "nenhum negócio encontrado para o ticker "+ticker+" no período")
+	}
+	if err != nil {
+		logger.Error("erro ao buscar volatilidade realizada",
+			zap.String("ticker", ticker),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao buscar volatilidade realizada")
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) GetTickerReturns(c *fiber.Ctx) error {
+	ticker := strings.ToUpper(c.Params("ticker"))
+
+	returnType := strings.ToLower(c.Query("type", service.ReturnTypeSimple))
+	if !service.ValidReturnType(returnType) {
+		return errorResponse(c, fiber.StatusBadRequest, "type inválido (use simple ou log)")
+	}
+
+	freq := strings.ToLower(c.Query("freq", service.FrequencyDaily))
+	if !service.ValidFrequency(freq) {
+		return errorResponse(c, fiber.StatusBadRequest, "freq inválida (use daily, weekly ou monthly)")
+	}
+
+	startDate, err := parseDateQuery(c, "start")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	endDate, err := parseDateQuery(c, "end")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if endDate == nil {
+		latest, err := h.tradeService.LatestSession(c.Context())
+		if errors.Is(err, service.ErrNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "nenhum pregão carregado")
+		}
+		if err != nil {
+			logger.Error("erro ao buscar último pregão", zap.Error(err))
+			return errorResponse(c, fiber.StatusInternalServerError, "erro ao calcular retornos")
+		}
+		endDate = &latest
+	}
+	if startDate == nil {
+		start := endDate.AddDate(-1, 0, 0)
+		startDate = &start
+	}
+
+	if endDate.Before(*startDate) {
+		return errorResponse(c, fiber.StatusBadRequest, "end anterior a start")
+	}
+
+	result, err := h.analysisService.GetReturns(c.Context(), ticker, *startDate, *endDate, returnType, freq)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "fechamentos insuficientes para o ticker "+ticker+" no período")
+	}
+	if err != nil {
+		logger.Error("erro ao calcular retornos",
+			zap.String("ticker", ticker),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao calcular retornos")
+	}
+
+	return c.JSON(result)
+}
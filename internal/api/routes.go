@@ -32,6 +32,20 @@ func SetupRoutes(app *fiber.App, handler *Handler) {
 	ticker.Get("/:ticker/aggregation", handler.GetTickerAggregation)
 	ticker.Get("/:ticker/history", handler.GetTickerHistory)
 	ticker.Get("/:ticker/stats", handler.GetTickerStats)
+	ticker.Get("/:ticker/compare", handler.CompareTickerStats)
+	ticker.Get("/:ticker/candles", handler.GetTickerCandles)
+	ticker.Get("/:ticker/vwap", handler.GetTickerVWAP)
+	ticker.Post("/:ticker/vwap", handler.BenchmarkExecution)
+	ticker.Get("/:ticker/indicators", handler.GetTickerIndicators)
+	ticker.Get("/:ticker/volume-profile", handler.GetVolumeProfile)
+	ticker.Get("/:ticker/trade-sizes", handler.GetTradeSizes)
+	ticker.Get("/:ticker/intraday-profile", handler.GetIntradayProfile)
+	ticker.Get("/:ticker/realized-vol", handler.GetRealizedVolatility)
+	ticker.Get("/:ticker/liquidity", handler.GetTickerLiquidity)
+	ticker.Get("/:ticker/returns", handler.GetTickerReturns)
+	ticker.Get("/:ticker/gaps", handler.GetTickerGaps)
+
+	v1.Post("/aggregations", handler.GetTickerAggregations)
 
 	// Admin routes
 	admin := v1.Group("/admin")
@@ -40,12 +54,56 @@ func SetupRoutes(app *fiber.App, handler *Handler) {
 	admin.Delete("/cache/:pattern", handler.InvalidateCache)
 	admin.Get("/stats", handler.GetSystemStats)
 	admin.Post("/load", handler.LoadDataFromFile)
+	admin.Post("/verify", handler.VerifyLoad)
 
 	// Analysis routes
 	analysis := v1.Group("/analysis")
 	analysis.Get("/top-volume", handler.GetTopVolume)
 	analysis.Get("/price-range", handler.GetPriceRange)
 	analysis.Get("/volatility", handler.GetVolatility)
+	analysis.Get("/volume-ranking", handler.GetVolumeRanking)
+	analysis.Get("/liquidity", handler.GetLiquidityRanking)
+	analysis.Post("/correlation", handler.GetCorrelation)
+
+	// Alert routes (regras disparam webhooks para URLs arbitrárias, que
+	// podem carregar tokens)
+	alerts := v1.Group("/alerts")
+	alerts.Post("/rules", BasicAuth(), handler.CreateAlertRule)
+	alerts.Get("/rules", BasicAuth(), handler.ListAlertRules)
+	alerts.Delete("/rules/:id", BasicAuth(), handler.DeleteAlertRule)
+	alerts.Get("/events", handler.ListAlertEvents)
+
+	// Portfolio routes
+	portfolios := v1.Group("/portfolios")
+	portfolios.Post("/", handler.CreatePortfolio)
+	portfolios.Get("/", handler.ListPortfolios)
+	portfolios.Get("/:id", handler.GetPortfolio)
+	portfolios.Put("/:id", handler.UpdatePortfolio)
+	portfolios.Delete("/:id", handler.DeletePortfolio)
+	portfolios.Post("/:id/positions", handler.AddPosition)
+	portfolios.Put("/:id/positions/:positionId", handler.UpdatePosition)
+	portfolios.Delete("/:id/positions/:positionId", handler.DeletePosition)
+	portfolios.Get("/:id/valuation", handler.GetPortfolioValuation)
+
+	// Custom index routes (a criação recalcula a série desde base_date)
+	indexes := v1.Group("/indexes")
+	indexes.Post("/", BasicAuth(), handler.CreateIndex)
+	indexes.Get("/", handler.ListIndexes)
+	indexes.Get("/:code", handler.GetIndex)
+	indexes.Delete("/:code", BasicAuth(), handler.DeleteIndex)
+	indexes.Get("/:code/levels", handler.GetIndexLevels)
+
+	// Backtest routes
+	v1.Post("/backtests", handler.RunBacktest)
+
+	// Market routes
+	market := v1.Group("/market")
+	market.Get("/overview", handler.GetMarketOverview)
+	market.Get("/intraday-profile", handler.GetMarketIntradayProfile)
+	market.Get("/gaps", handler.GetGapRanking)
+
+	// Screener routes
+	v1.Post("/screener", handler.Screen)
 }
 
 func BasicAuth() fiber.Handler {
@@ -3,6 +3,8 @@ package api
 import (
 	"time"
 
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/pkg/backtest"
 	"github.com/shopspring/decimal"
 )
 
@@ -19,6 +21,26 @@ type TickerAggregationResponse struct {
 	ProcessingTime string          `json:"processing_time,omitempty"`
 }
 
+// BatchAggregationRequest usa o mesmo período de TickerAggregationRequest
+// para uma lista de tickers.
+type BatchAggregationRequest struct {
+	Tickers   []string `json:"tickers" validate:"required"`
+	StartDate string   `json:"start_date,omitempty"`
+	EndDate   string   `json:"end_date,omitempty"`
+}
+
+type BatchAggregationResponse struct {
+	Data           []TickerAggregationResponse `json:"data"`
+	Errors         []TickerAggregationError    `json:"errors"`
+	CacheHits      int                         `json:"cache_hits"`
+	ProcessingTime string                      `json:"processing_time"`
+}
+
+type TickerAggregationError struct {
+	Ticker string `json:"ticker"`
+	Error  string `json:"error"`
+}
+
 type HealthResponse struct {
 	Status    string                   `json:"status"`
 	Version   string                   `json:"version"`
@@ -81,23 +103,32 @@ type LoadDataResponse struct {
 	Message      string `json:"message"`
 }
 
+type VerifyRequest struct {
+	Date     string `json:"date" validate:"required"`
+	FilePath string `json:"file_path"`
+}
+
 type TickerStatsRequest struct {
 	Days int `query:"days" default:"30"`
 }
 
 type TickerStatsResponse struct {
-	Ticker         string          `json:"ticker"`
-	Period         string          `json:"period"`
-	TotalVolume    int64           `json:"total_volume"`
-	TotalTrades    int             `json:"total_trades"`
-	AvgDailyVolume int64           `json:"avg_daily_volume"`
-	AvgPrice       decimal.Decimal `json:"avg_price"`
-	MinPrice       decimal.Decimal `json:"min_price"`
-	MaxPrice       decimal.Decimal `json:"max_price"`
-	PriceRange     decimal.Decimal `json:"price_range"`
-	Volatility     float64         `json:"volatility"`
-	DaysTraded     int             `json:"days_traded"`
-	LastUpdate     time.Time       `json:"last_update"`
+	Ticker          string          `json:"ticker"`
+	Period          string          `json:"period"`
+	TotalVolume     int64           `json:"total_volume"`
+	TotalTrades     int             `json:"total_trades"`
+	AvgDailyVolume  int64           `json:"avg_daily_volume"`
+	AvgPrice        decimal.Decimal `json:"avg_price"`
+	MinPrice        decimal.Decimal `json:"min_price"`
+	MaxPrice        decimal.Decimal `json:"max_price"`
+	PriceRange      decimal.Decimal `json:"price_range"`
+	OpenPrice       decimal.Decimal `json:"open_price"`
+	ClosePrice      decimal.Decimal `json:"close_price"`
+	VWAP            decimal.Decimal `json:"vwap"`
+	FinancialVolume decimal.Decimal `json:"financial_volume"`
+	Volatility      float64         `json:"volatility"`
+	DaysTraded      int             `json:"days_traded"`
+	LastUpdate      time.Time       `json:"last_update"`
 }
 
 type MarketOverviewResponse struct {
@@ -119,3 +150,76 @@ type PriceMovementDTO struct {
 	DayHigh       string `json:"day_high"`
 	DayLow        string `json:"day_low"`
 }
+
+type CandlesResponse struct {
+	Ticker    string          `json:"ticker"`
+	Interval  string          `json:"interval"`
+	StartDate string          `json:"start_date"`
+	EndDate   string          `json:"end_date"`
+	Candles   []domain.Candle `json:"candles"`
+	Count     int             `json:"count"`
+}
+
+type VWAPRequest struct {
+	Date     string        `json:"date" validate:"required"`
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Interval string        `json:"interval"`
+	Fills    []domain.Fill `json:"fills"`
+}
+
+type CorrelationRequest struct {
+	Tickers   []string `json:"tickers" validate:"required"`
+	StartDate string   `json:"start_date" validate:"required"`
+	EndDate   string   `json:"end_date" validate:"required"`
+	Frequency string   `json:"frequency"`
+	Spearman  bool     `json:"spearman"`
+	Benchmark string   `json:"benchmark"`
+}
+
+type AlertRuleRequest struct {
+	Name       string          `json:"name"`
+	Ticker     string          `json:"ticker" validate:"required"`
+	Condition  string          `json:"condition" validate:"required"`
+	Threshold  decimal.Decimal `json:"threshold" validate:"required"`
+	Lookback   int             `json:"lookback"`
+	WebhookURL string          `json:"webhook_url"`
+}
+
+type PortfolioRequest struct {
+	Name        string            `json:"name" validate:"required"`
+	Description string            `json:"description"`
+	Positions   []PositionRequest `json:"positions"`
+}
+
+type PositionRequest struct {
+	Ticker    string          `json:"ticker" validate:"required"`
+	Quantity  int64           `json:"quantity" validate:"required"`
+	CostBasis decimal.Decimal `json:"cost_basis" validate:"required"`
+	TradeDate string          `json:"trade_date" validate:"required"`
+}
+
+type BacktestRequest struct {
+	Tickers   []string          `json:"tickers" validate:"required"`
+	StartDate string            `json:"start_date" validate:"required"`
+	EndDate   string            `json:"end_date" validate:"required"`
+	Strategy  backtest.Strategy `json:"strategy" validate:"required"`
+}
+
+// ScreenerRequest recebe as datas como texto; os demais campos seguem
+// domain.AggregationFilter.
+type ScreenerRequest struct {
+	domain.AggregationFilter
+	StartDate string `json:"start_date,omitempty"`
+	EndDate   string `json:"end_date,omitempty"`
+}
+
+type IndexRequest struct {
+	Code         string                    `json:"code" validate:"required"`
+	Name         string                    `json:"name"`
+	Weighting    string                    `json:"weighting" validate:"required"`
+	Rebalance    string                    `json:"rebalance"`
+	BaseDate     string                    `json:"base_date" validate:"required"`
+	BaseValue    decimal.Decimal           `json:"base_value"`
+	Constituents []domain.IndexConstituent `json:"constituents" validate:"required"`
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) Screen(c *fiber.Ctx) error {
+	var req ScreenerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "corpo da requisição inválido")
+	}
+
+	filter := req.AggregationFilter
+
+	if req.StartDate != "" {
+		startDate, err := parseDate(req.StartDate)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "start_date: "+err.Error())
+		}
+		filter.StartDate = &startDate
+	}
+
+	if req.EndDate != "" {
+		endDate, err := parseDate(req.EndDate)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "end_date: "+err.Error())
+		}
+		filter.EndDate = &endDate
+	}
+
+	if err := service.NormalizeScreenerFilter(&filter); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.analysisService.Screen(c.Context(), filter)
+	if err != nil {
+		logger.Error("erro ao executar screener",
+			zap.String("sort_by", filter.SortBy),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao executar screener")
+	}
+
+	return c.JSON(result)
+}
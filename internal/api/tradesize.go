@@ -0,0 +1,81 @@
+package api
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) GetTradeSizes(c *fiber.Ctx) error {
+	ticker := strings.ToUpper(c.Params("ticker"))
+
+	startDate, err := parseDateQuery(c, "start")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	endDate, err := parseDateQuery(c, "end")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if endDate == nil {
+		latest, err := h.tradeService.LatestSession(c.Context())
+		if errors.Is(err, service.ErrNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "nenhum pregão carregado")
+		}
+		if err != nil {
+			logger.Error("erro ao buscar último pregão", zap.Error(err))
+			return errorResponse(c, fiber.StatusInternalServerError, "erro ao buscar tamanhos de negócio")
+		}
+		endDate = &latest
+	}
+	if startDate == nil {
+		startDate = endDate
+	}
+
+	if endDate.Before(*startDate) {
+		return errorResponse(c, fiber.StatusBadRequest, "end anterior a start")
+	}
+
+	by := c.Query("by", service.TradeSizeByFinancial)
+	if !service.ValidTradeSizeBy(by) {
+		return errorResponse(c, fiber.StatusBadRequest, "by inválido (use quantity, financial ou percentile)")
+	}
+
+	var threshold *decimal.Decimal
+	if value := c.Query("block_threshold"); value != "" {
+		parsed, err := decimal.NewFromString(value)
+		if err != nil || !parsed.IsPositive() {
+			return errorResponse(c, fiber.StatusBadRequest, "block_threshold deve ser positivo")
+		}
+		if by == service.TradeSizeByPercentile && parsed.GreaterThanOrEqual(decimal.NewFromInt(100)) {
+			return errorResponse(c, fiber.StatusBadRequest, "block_threshold deve estar entre 0 e 100 no modo percentile")
+		}
+		threshold = &parsed
+	}
+
+	limit := c.QueryInt("limit", 100)
+	if limit <= 0 || limit > 1000 {
+		return errorResponse(c, fiber.StatusBadRequest, "limit deve estar entre 1 e 1000")
+	}
+
+	result, err := h.candleService.GetTradeSizes(c.Context(), ticker, *startDate, *endDate, by, threshold, limit)
+	if errors.Is(err, service.ErrNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "nenhum negócio encontrado para o ticker "+ticker+" no período")
+	}
+	if err != nil {
+		logger.Error("erro ao buscar tamanhos de negócio",
+			zap.String("ticker", ticker),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao buscar tamanhos de negócio")
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeovahfialho/b3-analyzer/internal/service"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) GetTickerVWAP(c *fiber.Ctx) error {
+	req := VWAPRequest{
+		Date:     c.Query("date"),
+		From:     c.Query("from"),
+		To:       c.Query("to"),
+		Interval: c.Query("interval", "1m"),
+	}
+
+	return h.respondVWAP(c, req)
+}
+
+func (h *Handler) BenchmarkExecution(c *fiber.Ctx) error {
+	var req VWAPRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "corpo da requisição inválido")
+	}
+
+	if req.Interval == "" {
+		req.Interval = "1m"
+	}
+
+	if len(req.Fills) == 0 {
+		return errorResponse(c, fiber.StatusBadRequest, "informe ao menos um fill")
+	}
+
+	return h.respondVWAP(c, req)
+}
+
+func (h *Handler) respondVWAP(c *fiber.Ctx, req VWAPRequest) error {
+	ticker := c.Params("ticker")
+
+	date, err := parseDate(req.Date)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if _, err := service.ParseCandleInterval(req.Interval); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	for _, value := range []string{req.From, req.To} {
+		if value == "" {
+			continue
+		}
+		if _, err := service.ParseTimeOfDay(value); err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	if err := service.ValidateFills(req.Fills); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	curve, err := h.candleService.GetVWAPCurve(c.Context(), ticker, date, req.From, req.To, req.Interval, req.Fills)
+	if err != nil {
+		logger.Error("erro ao calcular curva de VWAP",
+			zap.String("ticker", ticker),
+			zap.Error(err))
+
+		return errorResponse(c, fiber.StatusInternalServerError, "erro ao calcular curva de VWAP")
+	}
+
+	if len(curve.Points) == 0 {
+		return errorResponse(c, fiber.StatusNotFound, "nenhum negócio encontrado na janela")
+	}
+
+	return c.JSON(curve)
+}
@@ -15,8 +15,16 @@ type Config struct {
 	RedisURL string        `envconfig:"REDIS_URL" default:"redis://localhost:6379"`
 	CacheTTL time.Duration `envconfig:"CACHE_TTL" default:"1h"`
 
-	BatchSize int `envconfig:"BATCH_SIZE" default:"10000"`
-	Workers   int `envconfig:"WORKERS" default:"4"`
+	CandleFill           string   `envconfig:"CANDLE_FILL" default:"none"`
+	CandleCacheIntervals []string `envconfig:"CANDLE_CACHE_INTERVALS" default:"1m,5m"`
+
+	AlertWebhookURLs    []string      `envconfig:"ALERT_WEBHOOK_URLS"`
+	AlertWebhookRetries int           `envconfig:"ALERT_WEBHOOK_RETRIES" default:"3"`
+	AlertWebhookTimeout time.Duration `envconfig:"ALERT_WEBHOOK_TIMEOUT" default:"5s"`
+
+	BatchSize int    `envconfig:"BATCH_SIZE" default:"10000"`
+	Workers   int    `envconfig:"WORKERS" default:"4"`
+	DataDir   string `envconfig:"DATA_DIR" default:"./data"`
 
 	APIHost         string        `envconfig:"API_HOST" default:"0.0.0.0"`
 	APIPort         string        `envconfig:"API_PORT" default:"8000"`
@@ -6,45 +6,86 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// Aggregation é o resumo de um ticker no período. Os campos opcionais são
+// preenchidos pelo screener.
 type Aggregation struct {
-	Ticker         string          `json:"ticker"`
-	MaxRangeValue  decimal.Decimal `json:"max_range_value"`
-	MaxDailyVolume int64           `json:"max_daily_volume"`
+	Ticker            string           `json:"ticker"`
+	MaxRangeValue     decimal.Decimal  `json:"max_range_value"`
+	MaxDailyVolume    int64            `json:"max_daily_volume"`
+	MinPrice          *decimal.Decimal `json:"min_price,omitempty"`
+	LastClose         *decimal.Decimal `json:"last_close,omitempty"`
+	PriceRangePercent *float64         `json:"price_range_percent,omitempty"`
+	AvgDailyVolume    *int64           `json:"avg_daily_volume,omitempty"`
+	Volatility        *float64         `json:"volatility,omitempty"`
+	ReturnPercent     *float64         `json:"return_percent,omitempty"`
+	Sessions          int              `json:"sessions,omitempty"`
 }
 
+// DailyAggregation é a linha do histórico. Nas granularidades acima de dia,
+// DataNegocio é o primeiro pregão do período e PeriodEnd o último.
 type DailyAggregation struct {
-	CodigoInstrumento string          `db:"codigo_instrumento" json:"codigo_instrumento"`
-	DataNegocio       time.Time       `db:"data_negocio" json:"data_negocio"`
-	MaxPrice          decimal.Decimal `db:"max_price" json:"max_price"`
-	MinPrice          decimal.Decimal `db:"min_price" json:"min_price"`
-	AvgPrice          decimal.Decimal `db:"avg_price" json:"avg_price"`
-	TotalVolume       int64           `db:"total_volume" json:"total_volume"`
-	TradeCount        int             `db:"trade_count" json:"trade_count"`
-	PriceStdDev       decimal.Decimal `db:"price_stddev" json:"price_stddev,omitempty"`
+	CodigoInstrumento  string          `db:"codigo_instrumento" json:"codigo_instrumento"`
+	DataNegocio        time.Time       `db:"data_negocio" json:"data_negocio"`
+	MaxPrice           decimal.Decimal `db:"max_price" json:"max_price"`
+	MinPrice           decimal.Decimal `db:"min_price" json:"min_price"`
+	AvgPrice           decimal.Decimal `db:"avg_price" json:"avg_price"`
+	TotalVolume        int64           `db:"total_volume" json:"total_volume"`
+	TradeCount         int             `db:"trade_count" json:"trade_count"`
+	PriceStdDev        decimal.Decimal `db:"price_stddev" json:"price_stddev,omitempty"`
+	OpenPrice          decimal.Decimal `db:"open_price" json:"open_price"`
+	ClosePrice         decimal.Decimal `db:"close_price" json:"close_price"`
+	VWAP               decimal.Decimal `db:"vwap" json:"vwap"`
+	FinancialVolume    decimal.Decimal `db:"financial_volume" json:"financial_volume"`
+	BlockVolume        int64           `db:"block_volume" json:"block_volume"`
+	BlockVolumePercent float64         `json:"block_volume_percent"`
+	PeriodEnd          *time.Time      `json:"period_end,omitempty"`
+	TradingDays        int             `json:"trading_days,omitempty"`
+	Partial            bool            `json:"partial,omitempty"`
 }
 
 type TickerStats struct {
-	Ticker         string             `json:"ticker"`
-	Period         string             `json:"period"`
-	TotalVolume    int64              `json:"total_volume"`
-	TotalTrades    int                `json:"total_trades"`
-	AvgDailyVolume int64              `json:"avg_daily_volume"`
-	AvgPrice       decimal.Decimal    `json:"avg_price"`
-	MinPrice       decimal.Decimal    `json:"min_price"`
-	MaxPrice       decimal.Decimal    `json:"max_price"`
-	PriceRange     decimal.Decimal    `json:"price_range"`
-	Volatility     float64            `json:"volatility"`
-	DaysTraded     int                `json:"days_traded"`
-	LastUpdate     time.Time          `json:"last_update"`
-	DailyStats     []DailyAggregation `json:"daily_stats,omitempty"`
+	Ticker          string             `json:"ticker"`
+	Period          string             `json:"period"`
+	TotalVolume     int64              `json:"total_volume"`
+	TotalTrades     int                `json:"total_trades"`
+	AvgDailyVolume  int64              `json:"avg_daily_volume"`
+	AvgPrice        decimal.Decimal    `json:"avg_price"`
+	MinPrice        decimal.Decimal    `json:"min_price"`
+	MaxPrice        decimal.Decimal    `json:"max_price"`
+	PriceRange      decimal.Decimal    `json:"price_range"`
+	OpenPrice       decimal.Decimal    `json:"open_price"`
+	ClosePrice      decimal.Decimal    `json:"close_price"`
+	VWAP            decimal.Decimal    `json:"vwap"`
+	FinancialVolume decimal.Decimal    `json:"financial_volume"`
+	Volatility      float64            `json:"volatility"`
+	DaysTraded      int                `json:"days_traded"`
+	LastUpdate      time.Time          `json:"last_update"`
+	DailyStats      []DailyAggregation `json:"daily_stats,omitempty"`
 }
 
+// AggregationFilter seleciona tickers pelas métricas do período
+// [StartDate, EndDate] ou, sem datas, dos últimos Days pregões. Os limites
+// de volume se aplicam ao volume médio diário e os de preço ao último
+// fechamento.
 type AggregationFilter struct {
-	Tickers   []string   `json:"tickers,omitempty"`
-	StartDate *time.Time `json:"start_date,omitempty"`
-	EndDate   *time.Time `json:"end_date,omitempty"`
-	MinVolume *int64     `json:"min_volume,omitempty"`
-	MaxVolume *int64     `json:"max_volume,omitempty"`
+	Tickers         []string         `json:"tickers,omitempty"`
+	StartDate       *time.Time       `json:"start_date,omitempty"`
+	EndDate         *time.Time       `json:"end_date,omitempty"`
+	MinVolume       *int64           `json:"min_volume,omitempty"`
+	MaxVolume       *int64           `json:"max_volume,omitempty"`
+	Days            int              `json:"days,omitempty"`
+	MinPrice        *decimal.Decimal `json:"min_price,omitempty"`
+	MaxPrice        *decimal.Decimal `json:"max_price,omitempty"`
+	MinRangePercent *float64         `json:"min_range_percent,omitempty"`
+	MaxRangePercent *float64         `json:"max_range_percent,omitempty"`
+	MinVolatility   *float64         `json:"min_volatility,omitempty"`
+	MaxVolatility   *float64         `json:"max_volatility,omitempty"`
+	MinReturn       *float64         `json:"min_return,omitempty"`
+	MaxReturn       *float64         `json:"max_return,omitempty"`
+	SortBy          string           `json:"sort_by,omitempty"`
+	SortOrder       string           `json:"sort_order,omitempty"`
+	Limit           int              `json:"limit,omitempty"`
+	Cursor          string           `json:"cursor,omitempty"`
 }
 
 type AggregationResult struct {
@@ -53,6 +94,7 @@ type AggregationResult struct {
 	Page       int           `json:"page"`
 	PageSize   int           `json:"page_size"`
 	HasMore    bool          `json:"has_more"`
+	NextCursor string        `json:"next_cursor,omitempty"`
 }
 
 type VolumeRanking struct {
@@ -64,6 +106,30 @@ type VolumeRanking struct {
 	VolumeChangePercent float64 `json:"volume_change_percent"`
 }
 
+type VolumeRankingQuery struct {
+	Window            int      `json:"window"`
+	Compare           int      `json:"compare"`
+	Metric            string   `json:"metric"`
+	Tickers           []string `json:"tickers,omitempty"`
+	Prefix            string   `json:"prefix,omitempty"`
+	ExcludeFractional bool     `json:"exclude_fractional,omitempty"`
+	Page              int      `json:"page"`
+	PageSize          int      `json:"page_size"`
+}
+
+type VolumeRankingResult struct {
+	Metric      string          `json:"metric"`
+	Window      int             `json:"window"`
+	Compare     int             `json:"compare"`
+	WindowStart *time.Time      `json:"window_start,omitempty"`
+	WindowEnd   *time.Time      `json:"window_end,omitempty"`
+	Data        []VolumeRanking `json:"data"`
+	TotalCount  int             `json:"total_count"`
+	Page        int             `json:"page"`
+	PageSize    int             `json:"page_size"`
+	HasMore     bool            `json:"has_more"`
+}
+
 type PriceMovement struct {
 	Ticker        string          `json:"ticker"`
 	CurrentPrice  decimal.Decimal `json:"current_price"`
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type AlertRule struct {
+	ID         int64           `json:"id"`
+	Name       string          `json:"name"`
+	Ticker     string          `json:"ticker"`
+	Condition  string          `json:"condition"`
+	Threshold  decimal.Decimal `json:"threshold"`
+	Lookback   int             `json:"lookback"`
+	WebhookURL string          `json:"webhook_url,omitempty"`
+	Active     bool            `json:"active"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AlertEvent struct {
+	ID               int64           `json:"id"`
+	RuleID           int64           `json:"rule_id"`
+	RuleName         string          `json:"rule_name"`
+	Ticker           string          `json:"ticker"`
+	Condition        string          `json:"condition"`
+	Date             time.Time       `json:"date"`
+	Value            float64         `json:"value"`
+	Threshold        decimal.Decimal `json:"threshold"`
+	Message          string          `json:"message"`
+	TriggeredAt      time.Time       `json:"triggered_at"`
+	DeliveryStatus   string          `json:"delivery_status"`
+	DeliveryAttempts int             `json:"delivery_attempts"`
+	DeliveredAt      *time.Time      `json:"delivered_at,omitempty"`
+	LastError        string          `json:"last_error,omitempty"`
+}
+
+type AlertEventFilter struct {
+	RuleID   *int64
+	Ticker   string
+	Status   string
+	Page     int
+	PageSize int
+}
+
+type AlertEvaluation struct {
+	Date           time.Time    `json:"date"`
+	RulesEvaluated int          `json:"rules_evaluated"`
+	Fired          []AlertEvent `json:"fired"`
+}
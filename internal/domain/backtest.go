@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/pkg/backtest"
+)
+
+type BacktestResult struct {
+	Tickers   []string  `json:"tickers"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	backtest.Result
+}
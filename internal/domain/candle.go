@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Candle struct {
+	Time            time.Time       `json:"time"`
+	Open            decimal.Decimal `json:"open"`
+	High            decimal.Decimal `json:"high"`
+	Low             decimal.Decimal `json:"low"`
+	Close           decimal.Decimal `json:"close"`
+	Volume          int64           `json:"volume"`
+	FinancialVolume decimal.Decimal `json:"financial_volume"`
+	TradeCount      int             `json:"trade_count"`
+	Filled          bool            `json:"filled,omitempty"`
+}
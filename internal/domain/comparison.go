@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type StatsPeriod struct {
+	Name      string       `json:"name,omitempty"`
+	StartDate time.Time    `json:"start_date"`
+	EndDate   time.Time    `json:"end_date"`
+	Stats     *TickerStats `json:"stats"`
+}
+
+// MetricDelta compara uma métrica do período A com o período B: Change é
+// A - B e ChangePercent é relativo a B, ausente quando B é zero.
+type MetricDelta struct {
+	A             decimal.Decimal `json:"a"`
+	B             decimal.Decimal `json:"b"`
+	Change        decimal.Decimal `json:"change"`
+	ChangePercent *float64        `json:"change_percent"`
+}
+
+type TickerStatsComparison struct {
+	Ticker string                 `json:"ticker"`
+	A      StatsPeriod            `json:"a"`
+	B      StatsPeriod            `json:"b"`
+	Deltas map[string]MetricDelta `json:"deltas,omitempty"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+type CorrelationQuery struct {
+	Tickers   []string  `json:"tickers"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	Frequency string    `json:"frequency"`
+	Spearman  bool      `json:"spearman"`
+	Benchmark string    `json:"benchmark,omitempty"`
+}
+
+type TickerBeta struct {
+	Ticker      string   `json:"ticker"`
+	Beta        *float64 `json:"beta"`
+	Correlation *float64 `json:"correlation"`
+}
+
+type CorrelationResult struct {
+	Tickers      []string     `json:"tickers"`
+	Benchmark    string       `json:"benchmark,omitempty"`
+	StartDate    time.Time    `json:"start_date"`
+	EndDate      time.Time    `json:"end_date"`
+	Frequency    string       `json:"frequency"`
+	Observations int          `json:"observations"`
+	Pearson      [][]*float64 `json:"pearson"`
+	Spearman     [][]*float64 `json:"spearman,omitempty"`
+	Betas        []TickerBeta `json:"betas,omitempty"`
+	CacheHit     bool         `json:"cache_hit,omitempty"`
+}
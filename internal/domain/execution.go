@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type VWAPPoint struct {
+	Time             time.Time       `json:"time"`
+	Close            decimal.Decimal `json:"close"`
+	Volume           int64           `json:"volume"`
+	CumulativeVolume int64           `json:"cumulative_volume"`
+	VWAP             decimal.Decimal `json:"vwap"`
+	TWAP             decimal.Decimal `json:"twap"`
+}
+
+type Fill struct {
+	Time     string          `json:"time"`
+	Price    decimal.Decimal `json:"price"`
+	Quantity int64           `json:"quantity"`
+	Side     string          `json:"side"`
+}
+
+type ExecutionBenchmark struct {
+	Side              string          `json:"side"`
+	Fills             int             `json:"fills"`
+	Quantity          int64           `json:"quantity"`
+	AvgPrice          decimal.Decimal `json:"avg_price"`
+	VWAP              decimal.Decimal `json:"vwap"`
+	TWAP              decimal.Decimal `json:"twap"`
+	SlippageVWAPBps   float64         `json:"slippage_vwap_bps"`
+	SlippageTWAPBps   float64         `json:"slippage_twap_bps"`
+	ParticipationRate float64         `json:"participation_rate"`
+}
+
+type VWAPCurve struct {
+	Ticker     string               `json:"ticker"`
+	Date       time.Time            `json:"date"`
+	From       string               `json:"from,omitempty"`
+	To         string               `json:"to,omitempty"`
+	Interval   string               `json:"interval"`
+	VWAP       decimal.Decimal      `json:"vwap"`
+	TWAP       decimal.Decimal      `json:"twap"`
+	Volume     int64                `json:"volume"`
+	Points     []VWAPPoint          `json:"points"`
+	Benchmarks []ExecutionBenchmark `json:"benchmarks,omitempty"`
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OpeningGap compara a abertura do pregão com o fechamento do pregão
+// anterior carregado. O gap de alta fecha no primeiro negócio a
+// PrevClose ou abaixo; o de baixa, no primeiro a PrevClose ou acima.
+// MinutesToFill conta a partir do primeiro negócio do pregão.
+type OpeningGap struct {
+	Ticker        string          `json:"ticker"`
+	Date          time.Time       `json:"date"`
+	PrevDate      time.Time       `json:"prev_date"`
+	PrevClose     decimal.Decimal `json:"prev_close"`
+	Open          decimal.Decimal `json:"open"`
+	Close         decimal.Decimal `json:"close"`
+	GapPercent    float64         `json:"gap_percent"`
+	Direction     string          `json:"direction"`
+	Filled        bool            `json:"filled"`
+	OpenedAt      string          `json:"opened_at,omitempty"`
+	FilledAt      string          `json:"filled_at,omitempty"`
+	MinutesToFill *float64        `json:"minutes_to_fill,omitempty"`
+}
+
+type GapStats struct {
+	Gaps             int     `json:"gaps"`
+	Filled           int     `json:"filled"`
+	FillRate         float64 `json:"fill_rate"`
+	AvgGapPercent    float64 `json:"avg_gap_percent"`
+	AvgMinutesToFill float64 `json:"avg_minutes_to_fill"`
+}
+
+// GapSummary traz as estatísticas gerais e separadas por direção; FillRate
+// e AvgGapPercent (em módulo) estão em percentual.
+type GapSummary struct {
+	GapStats
+	Up   GapStats `json:"up"`
+	Down GapStats `json:"down"`
+}
+
+type GapAnalysis struct {
+	Ticker     string       `json:"ticker"`
+	StartDate  time.Time    `json:"start_date"`
+	EndDate    time.Time    `json:"end_date"`
+	MinPercent float64      `json:"min_pct"`
+	Summary    GapSummary   `json:"summary"`
+	Gaps       []OpeningGap `json:"gaps"`
+}
+
+type GapRanking struct {
+	Date       time.Time    `json:"date"`
+	MinPercent float64      `json:"min_pct"`
+	Summary    GapSummary   `json:"summary"`
+	Data       []OpeningGap `json:"data"`
+}
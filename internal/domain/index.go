@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CustomIndex é uma cesta de tickers acompanhada como índice a partir de
+// BaseValue no primeiro pregão a partir de BaseDate. Os pesos são fixos
+// (informados por constituinte), iguais ou proporcionais ao volume
+// financeiro, reaplicados no primeiro pregão de cada período de Rebalance.
+type CustomIndex struct {
+	ID           int64              `json:"id"`
+	Code         string             `json:"code"`
+	Ticker       string             `json:"ticker"`
+	Name         string             `json:"name"`
+	Weighting    string             `json:"weighting"`
+	Rebalance    string             `json:"rebalance"`
+	BaseDate     time.Time          `json:"base_date"`
+	BaseValue    decimal.Decimal    `json:"base_value"`
+	CreatedAt    time.Time          `json:"created_at"`
+	Constituents []IndexConstituent `json:"constituents,omitempty"`
+	LastLevel    *IndexLevel        `json:"last_level,omitempty"`
+}
+
+type IndexConstituent struct {
+	Ticker string           `json:"ticker"`
+	Weight *decimal.Decimal `json:"weight,omitempty"`
+}
+
+// IndexLevel é o índice no pregão. Volume, financeiro e negócios somam os
+// constituintes; Rebalanced marca os pregões em que os pesos foram
+// reaplicados.
+type IndexLevel struct {
+	Date            time.Time       `json:"date"`
+	Open            decimal.Decimal `json:"open"`
+	Close           decimal.Decimal `json:"close"`
+	TotalVolume     int64           `json:"total_volume"`
+	FinancialVolume decimal.Decimal `json:"financial_volume"`
+	TradeCount      int             `json:"trade_count"`
+	Rebalanced      bool            `json:"rebalanced,omitempty"`
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+type IndicatorSeries struct {
+	Ticker     string                           `json:"ticker"`
+	Dates      []time.Time                      `json:"dates"`
+	Close      []float64                        `json:"close"`
+	Indicators map[string]map[string][]*float64 `json:"indicators"`
+}
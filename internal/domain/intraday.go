@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// IntradayBucket traz médias entre os pregões da janela, em percentual: as
+// participações no volume e nos negócios do dia (pregões sem negócios na
+// faixa contam como zero) e o retorno absoluto entre o fechamento da faixa
+// e o da faixa anterior.
+type IntradayBucket struct {
+	Time                  string  `json:"time"`
+	VolumeShare           float64 `json:"volume_share"`
+	CumulativeVolumeShare float64 `json:"cumulative_volume_share"`
+	TradeShare            float64 `json:"trade_share"`
+	AvgAbsReturn          float64 `json:"avg_abs_return"`
+	Sessions              int     `json:"sessions"`
+}
+
+type IntradayProfile struct {
+	Ticker    string           `json:"ticker,omitempty"`
+	Bucket    string           `json:"bucket"`
+	Days      int              `json:"days"`
+	Sessions  int              `json:"sessions"`
+	StartDate time.Time        `json:"start_date"`
+	EndDate   time.Time        `json:"end_date"`
+	Buckets   []IntradayBucket `json:"buckets"`
+	CacheHit  bool             `json:"cache_hit,omitempty"`
+}
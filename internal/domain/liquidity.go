@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// LiquidityMetrics reúne as medidas de liquidez do ticker no pregão.
+// Amihud é o retorno absoluto fechamento a fechamento (%) por R$ 1 milhão
+// negociado; RollSpread é o spread implícito de Roll em preço e em bps do
+// preço médio, ausente quando a autocovariância das variações é positiva.
+type LiquidityMetrics struct {
+	Rank                    int             `json:"rank,omitempty"`
+	Ticker                  string          `json:"ticker"`
+	Date                    time.Time       `json:"date"`
+	ReturnPercent           *float64        `json:"return_percent"`
+	Amihud                  *float64        `json:"amihud"`
+	RollSpread              *float64        `json:"roll_spread"`
+	RollSpreadBps           *float64        `json:"roll_spread_bps"`
+	TradesPerMinute         float64         `json:"trades_per_minute"`
+	MedianInterTradeSeconds *float64        `json:"median_inter_trade_seconds"`
+	AvgTradeSize            int64           `json:"avg_trade_size"`
+	AvgTradeFinancial       decimal.Decimal `json:"avg_trade_financial"`
+	TradeCount              int             `json:"trade_count"`
+	FinancialVolume         decimal.Decimal `json:"financial_volume"`
+}
+
+type LiquidityRanking struct {
+	Date       time.Time          `json:"date"`
+	SortBy     string             `json:"sort_by"`
+	Data       []LiquidityMetrics `json:"data"`
+	TotalCount int                `json:"total_count"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"page_size"`
+	HasMore    bool               `json:"has_more"`
+	CacheHit   bool               `json:"cache_hit,omitempty"`
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Portfolio struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Positions   []Position `json:"positions,omitempty"`
+}
+
+type Position struct {
+	ID          int64           `json:"id"`
+	PortfolioID int64           `json:"portfolio_id"`
+	Ticker      string          `json:"ticker"`
+	Quantity    int64           `json:"quantity"`
+	CostBasis   decimal.Decimal `json:"cost_basis"`
+	TradeDate   time.Time       `json:"trade_date"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type PositionValuation struct {
+	PositionID      int64            `json:"position_id"`
+	Ticker          string           `json:"ticker"`
+	Quantity        int64            `json:"quantity"`
+	CostBasis       decimal.Decimal  `json:"cost_basis"`
+	TradeDate       time.Time        `json:"trade_date"`
+	PriceDate       *time.Time       `json:"price_date"`
+	Close           *decimal.Decimal `json:"close"`
+	PreviousClose   *decimal.Decimal `json:"previous_close,omitempty"`
+	Cost            decimal.Decimal  `json:"cost"`
+	MarketValue     decimal.Decimal  `json:"market_value"`
+	DailyPnL        decimal.Decimal  `json:"daily_pnl"`
+	TotalPnL        decimal.Decimal  `json:"total_pnl"`
+	TotalPnLPercent float64          `json:"total_pnl_percent"`
+	Weight          float64          `json:"weight"`
+}
+
+type NAVPoint struct {
+	Date        time.Time       `json:"date"`
+	MarketValue decimal.Decimal `json:"market_value"`
+	Cost        decimal.Decimal `json:"cost"`
+	PnL         decimal.Decimal `json:"pnl"`
+}
+
+type PortfolioValuation struct {
+	PortfolioID     int64               `json:"portfolio_id"`
+	Name            string              `json:"name"`
+	Date            time.Time           `json:"date"`
+	Cost            decimal.Decimal     `json:"cost"`
+	MarketValue     decimal.Decimal     `json:"market_value"`
+	DailyPnL        decimal.Decimal     `json:"daily_pnl"`
+	TotalPnL        decimal.Decimal     `json:"total_pnl"`
+	TotalPnLPercent float64             `json:"total_pnl_percent"`
+	Unpriced        []string            `json:"unpriced,omitempty"`
+	Positions       []PositionValuation `json:"positions"`
+	NAV             []NAVPoint          `json:"nav"`
+}
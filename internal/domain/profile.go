@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type VolumeProfileBucket struct {
+	PriceLow  decimal.Decimal `json:"price_low"`
+	PriceHigh decimal.Decimal `json:"price_high"`
+	Volume    int64           `json:"volume"`
+	Trades    int             `json:"trades"`
+	Percent   float64         `json:"percent"`
+}
+
+// VolumeProfile é o histograma de volume por preço. PointOfControl é o
+// centro da faixa de maior volume e a value area é o intervalo contíguo em
+// torno dela que concentra ValueAreaPercent do volume.
+type VolumeProfile struct {
+	Ticker           string                `json:"ticker"`
+	StartDate        time.Time             `json:"start_date"`
+	EndDate          time.Time             `json:"end_date"`
+	Mode             string                `json:"mode"`
+	BucketWidth      decimal.Decimal       `json:"bucket_width"`
+	TotalVolume      int64                 `json:"total_volume"`
+	TotalTrades      int                   `json:"total_trades"`
+	PointOfControl   decimal.Decimal       `json:"point_of_control"`
+	ValueAreaHigh    decimal.Decimal       `json:"value_area_high"`
+	ValueAreaLow     decimal.Decimal       `json:"value_area_low"`
+	ValueAreaPercent float64               `json:"value_area_percent"`
+	Buckets          []VolumeProfileBucket `json:"buckets"`
+}
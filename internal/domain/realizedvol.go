@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// RealizedVolPoint é a variância realizada do pregão (soma dos log-retornos
+// intradiários ao quadrado) e a volatilidade anualizada correspondente.
+type RealizedVolPoint struct {
+	Date               time.Time `json:"date"`
+	Observations       int       `json:"observations"`
+	RealizedVariance   float64   `json:"realized_variance"`
+	RealizedVolatility float64   `json:"realized_volatility"`
+}
+
+// RealizedVolCone resume a volatilidade anualizada de todas as janelas de
+// Horizon pregões da série; Current é a da janela mais recente.
+type RealizedVolCone struct {
+	Horizon int     `json:"horizon"`
+	Windows int     `json:"windows"`
+	Min     float64 `json:"min"`
+	P10     float64 `json:"p10"`
+	P25     float64 `json:"p25"`
+	P50     float64 `json:"p50"`
+	P75     float64 `json:"p75"`
+	P90     float64 `json:"p90"`
+	Max     float64 `json:"max"`
+	Current float64 `json:"current"`
+}
+
+type RealizedVolResult struct {
+	Ticker    string             `json:"ticker"`
+	Frequency string             `json:"frequency"`
+	StartDate time.Time          `json:"start_date"`
+	EndDate   time.Time          `json:"end_date"`
+	Series    []RealizedVolPoint `json:"series"`
+	Cones     []RealizedVolCone  `json:"cones"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type TickerReconciliation struct {
+	Ticker             string          `json:"ticker"`
+	SourceTrades       int64           `json:"source_trades"`
+	StoredTrades       int64           `json:"stored_trades"`
+	AggregatedTrades   int64           `json:"aggregated_trades"`
+	SourceQuantity     int64           `json:"source_quantity"`
+	StoredQuantity     int64           `json:"stored_quantity"`
+	AggregatedQuantity int64           `json:"aggregated_quantity"`
+	SourcePriceSum     decimal.Decimal `json:"source_price_sum"`
+	StoredPriceSum     decimal.Decimal `json:"stored_price_sum"`
+	AggregatedPriceSum decimal.Decimal `json:"aggregated_price_sum"`
+	Issues             []string        `json:"issues"`
+}
+
+type ReconciliationReport struct {
+	Date           time.Time              `json:"date"`
+	FilePath       string                 `json:"file_path"`
+	SourceRecords  int                    `json:"source_records"`
+	ParseErrors    int                    `json:"parse_errors"`
+	TickersChecked int                    `json:"tickers_checked"`
+	Consistent     bool                   `json:"consistent"`
+	Discrepancies  []TickerReconciliation `json:"discrepancies"`
+	CheckedAt      time.Time              `json:"checked_at"`
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// ReturnPoint traz o retorno do período e a posição da série em relação ao
+// pico anterior. Retornos e drawdown são frações (0.05 = 5%), com o drawdown
+// positivo como em stats.MaxDrawdown; Return é nulo no primeiro ponto, que
+// serve de base.
+type ReturnPoint struct {
+	Date             time.Time `json:"date"`
+	Close            float64   `json:"close"`
+	Return           *float64  `json:"return"`
+	CumulativeReturn float64   `json:"cumulative_return"`
+	RunningMax       float64   `json:"running_max"`
+	Drawdown         float64   `json:"drawdown"`
+}
+
+// DrawdownPeriod vai do pico até a recuperação do pico ou, se ainda não
+// recuperado, até o fim da série.
+type DrawdownPeriod struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Periods   int       `json:"periods"`
+	Days      int       `json:"days"`
+	Recovered bool      `json:"recovered"`
+}
+
+type ReturnsSummary struct {
+	TotalReturn          float64         `json:"total_return"`
+	AnnualizedReturn     float64         `json:"annualized_return"`
+	AnnualizedVolatility float64         `json:"annualized_volatility"`
+	MaxDrawdown          float64         `json:"max_drawdown"`
+	MaxDrawdownPeak      *time.Time      `json:"max_drawdown_peak,omitempty"`
+	MaxDrawdownTrough    *time.Time      `json:"max_drawdown_trough,omitempty"`
+	LongestDrawdown      *DrawdownPeriod `json:"longest_drawdown,omitempty"`
+}
+
+type ReturnsResult struct {
+	Ticker    string         `json:"ticker"`
+	Type      string         `json:"type"`
+	Frequency string         `json:"frequency"`
+	StartDate time.Time      `json:"start_date"`
+	EndDate   time.Time      `json:"end_date"`
+	Summary   ReturnsSummary `json:"summary"`
+	Series    []ReturnPoint  `json:"series"`
+}
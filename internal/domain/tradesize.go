@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TradeSizeBucket cobre os negócios com tamanho em [Min, Max); Min ausente
+// é a primeira faixa e Max ausente, a última.
+type TradeSizeBucket struct {
+	Label         string           `json:"label"`
+	Min           *decimal.Decimal `json:"min,omitempty"`
+	Max           *decimal.Decimal `json:"max,omitempty"`
+	Trades        int              `json:"trades"`
+	Volume        int64            `json:"volume"`
+	VolumePercent float64          `json:"volume_percent"`
+}
+
+type TradeSizeDay struct {
+	Date               time.Time         `json:"date"`
+	Trades             int               `json:"trades"`
+	Volume             int64             `json:"volume"`
+	Buckets            []TradeSizeBucket `json:"buckets"`
+	BlockTrades        int               `json:"block_trades"`
+	BlockVolume        int64             `json:"block_volume"`
+	BlockVolumePercent float64           `json:"block_volume_percent"`
+}
+
+type BlockTrade struct {
+	Date      time.Time       `json:"date"`
+	Time      string          `json:"time"`
+	Price     decimal.Decimal `json:"price"`
+	Quantity  int64           `json:"quantity"`
+	Financial decimal.Decimal `json:"financial"`
+}
+
+// TradeSizeAnalysis classifica os negócios por quantidade, financeiro ou
+// percentis de quantidade do próprio ticker no período. Edges e
+// BlockThreshold estão na unidade de By; no modo percentile, já convertidos
+// em quantidade.
+type TradeSizeAnalysis struct {
+	Ticker         string            `json:"ticker"`
+	StartDate      time.Time         `json:"start_date"`
+	EndDate        time.Time         `json:"end_date"`
+	By             string            `json:"by"`
+	Edges          []decimal.Decimal `json:"edges"`
+	BlockThreshold decimal.Decimal   `json:"block_threshold"`
+	Days           []TradeSizeDay    `json:"days"`
+	BlockTrades    []BlockTrade      `json:"block_trades"`
+}
@@ -0,0 +1,196 @@
+package ingestion
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+)
+
+// BlockTradeMinFinancial é o financeiro mínimo, em reais, de um negócio em
+// bloco na coluna block_volume de daily_aggregations.
+const BlockTradeMinFinancial = 1000000
+
+// dailyAggregationInsert consolida trades em daily_aggregations; recebe
+// BlockTradeMinFinancial e o filtro que restringe os pregões recalculados.
+const dailyAggregationInsert = `
+    INSERT INTO daily_aggregations (
+        codigo_instrumento, data_negocio, max_price, total_volume, trade_count,
+        min_price, avg_price, price_stddev, open_price, close_price,
+        financial_volume, vwap, block_volume
+    )
+    SELECT
+        t.codigo_instrumento,
+        t.data_negocio,
+        MAX(t.preco_negocio),
+        SUM(t.quantidade_negociada),
+        COUNT(*),
+        MIN(t.preco_negocio),
+        AVG(t.preco_negocio),
+        STDDEV(t.preco_negocio),
+        (ARRAY_AGG(t.preco_negocio ORDER BY t.hora_fechamento, t.id))[1],
+        (ARRAY_AGG(t.preco_negocio ORDER BY t.hora_fechamento DESC, t.id DESC))[1],
+        SUM(t.preco_negocio * t.quantidade_negociada),
+        SUM(t.preco_negocio * t.quantidade_negociada) / NULLIF(SUM(t.quantidade_negociada), 0),
+        COALESCE(SUM(t.quantidade_negociada) FILTER (WHERE t.preco_negocio * t.quantidade_negociada >= %d), 0)
+    FROM trades t
+    %s
+    GROUP BY t.codigo_instrumento, t.data_negocio
+`
+
+// dailyAggregationLock serializa as atualizações: como cada uma relê trades
+// depois de obter o lock, a última sempre vê os negócios de todas as cargas
+// já confirmadas, mesmo que duas cargas toquem o mesmo pregão.
+const dailyAggregationLock = "SELECT pg_advisory_xact_lock(hashtext('daily_aggregations'))"
+
+// DailyAggregationSharedLock é a forma compartilhada do mesmo lock, para
+// quem grava dados derivados de trades que as atualizações invalidam (como
+// realized_volatility): enquanto o leitor o segura, nenhuma carga limpa os
+// pregões, e depois dele a limpeza alcança o que foi gravado.
+const DailyAggregationSharedLock = "SELECT pg_advisory_xact_lock_shared(hashtext('daily_aggregations'))"
+
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+// SessionKey identifica um pregão de um ticker em daily_aggregations.
+type SessionKey struct {
+	Ticker string
+	Date   time.Time
+}
+
+// SessionKeys devolve os pares (ticker, pregão) distintos dos negócios.
+func SessionKeys(trades []domain.Trade) []SessionKey {
+	seen := make(map[SessionKey]bool)
+	var keys []SessionKey
+	for _, trade := range trades {
+		key := SessionKey{Ticker: trade.CodigoInstrumento, Date: trade.DataNegocio}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].Date.Equal(keys[j].Date) {
+			return keys[i].Date.Before(keys[j].Date)
+		}
+		return keys[i].Ticker < keys[j].Ticker
+	})
+	return keys
+}
+
+// SessionDates devolve os pregões distintos das chaves, em ordem.
+func SessionDates(keys []SessionKey) []time.Time {
+	var dates []time.Time
+	for _, key := range keys {
+		if len(dates) == 0 || !dates[len(dates)-1].Equal(key.Date) {
+			dates = append(dates, key.Date)
+		}
+	}
+	return dates
+}
+
+// RefreshDailyAggregations recalcula apenas os pregões informados, dentro da
+// transação recebida. Pares sem negócios em trades são removidos, e a
+// volatilidade realizada desses pregões é descartada para ser recalculada
+// na próxima consulta.
+func RefreshDailyAggregations(ctx context.Context, tx execer, keys []SessionKey) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	tickers := make([]string, len(keys))
+	dates := make([]time.Time, len(keys))
+	for i, key := range keys {
+		tickers[i] = key.Ticker
+		dates[i] = key.Date
+	}
+
+	if _, err := tx.Exec(ctx, dailyAggregationLock); err != nil {
+		return 0, fmt.Errorf("erro ao bloquear agregações: %w", err)
+	}
+
+	_, err := tx.Exec(ctx, `
+        DELETE FROM daily_aggregations d
+        USING UNNEST($1::text[], $2::date[]) AS k(ticker, day)
+        WHERE d.codigo_instrumento = k.ticker AND d.data_negocio = k.day
+    `, tickers, dates)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao limpar agregações: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+        DELETE FROM realized_volatility rv
+        USING UNNEST($1::text[], $2::date[]) AS k(ticker, day)
+        WHERE rv.codigo_instrumento = k.ticker AND rv.data_negocio = k.day
+    `, tickers, dates)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao limpar volatilidade realizada: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, fmt.Sprintf(dailyAggregationInsert, BlockTradeMinFinancial, `
+        JOIN UNNEST($1::text[], $2::date[]) AS k(ticker, day)
+          ON t.codigo_instrumento = k.ticker AND t.data_negocio = k.day
+        WHERE t.data_negocio = ANY($2::date[])
+    `), tickers, dates)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao atualizar agregações: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// RebuildDailyAggregations refaz daily_aggregations inteira a partir de
+// trades, descartando toda a volatilidade realizada já calculada. É o
+// reparo para cargas interrompidas ou correções feitas direto no banco; as
+// leituras ficam bloqueadas até o commit.
+func RebuildDailyAggregations(ctx context.Context, pool *pgxpool.Pool) (int64, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, dailyAggregationLock); err != nil {
+		return 0, fmt.Errorf("erro ao bloquear agregações: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, "TRUNCATE daily_aggregations, realized_volatility"); err != nil {
+		return 0, fmt.Errorf("erro ao limpar agregações: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, fmt.Sprintf(dailyAggregationInsert, BlockTradeMinFinancial, ""))
+	if err != nil {
+		return 0, fmt.Errorf("erro ao reconstruir agregações: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("erro no commit: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// refreshSessions recalcula os pregões em uma transação própria, após o
+// commit dos negócios.
+func refreshSessions(ctx context.Context, pool *pgxpool.Pool, keys []SessionKey) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := RefreshDailyAggregations(ctx, tx, keys); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro no commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package ingestion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+)
+
+func TestSessionKeys(t *testing.T) {
+	day1 := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	trades := []domain.Trade{
+		{CodigoInstrumento: "VALE3", DataNegocio: day2},
+		{CodigoInstrumento: "PETR4", DataNegocio: day1},
+		{CodigoInstrumento: "VALE3", DataNegocio: day2},
+		{CodigoInstrumento: "VALE3", DataNegocio: day1},
+		{CodigoInstrumento: "PETR4", DataNegocio: day1},
+	}
+
+	keys := SessionKeys(trades)
+	want := []SessionKey{{"PETR4", day1}, {"VALE3", day1}, {"VALE3", day2}}
+	if len(keys) != len(want) {
+		t.Fatalf("esperado %d pregões, recebido %d: %v", len(want), len(keys), keys)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("pregão %d: esperado %v, recebido %v", i, want[i], keys[i])
+		}
+	}
+
+	dates := SessionDates(keys)
+	if len(dates) != 2 || !dates[0].Equal(day1) || !dates[1].Equal(day2) {
+		t.Errorf("esperado pregões %v e %v, recebido %v", day1, day2, dates)
+	}
+}
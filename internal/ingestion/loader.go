@@ -21,7 +21,13 @@ func NewBulkLoader(pool *pgxpool.Pool, batchSize int) *BulkLoader {
 	}
 }
 
+// LoadTrades grava os negócios e recalcula os pregões tocados em
+// daily_aggregations na mesma transação.
 func (l *BulkLoader) LoadTrades(ctx context.Context, trades []domain.Trade) (int64, error) {
+	return l.loadTrades(ctx, trades, true)
+}
+
+func (l *BulkLoader) loadTrades(ctx context.Context, trades []domain.Trade, refresh bool) (int64, error) {
 	if len(trades) == 0 {
 		return 0, nil
 	}
@@ -51,6 +57,12 @@ func (l *BulkLoader) LoadTrades(ctx context.Context, trades []domain.Trade) (int
 		return 0, fmt.Errorf("erro no COPY: %w", err)
 	}
 
+	if refresh {
+		if _, err := RefreshDailyAggregations(ctx, tx, SessionKeys(trades)); err != nil {
+			return 0, err
+		}
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return 0, fmt.Errorf("erro no commit: %w", err)
 	}
@@ -87,37 +99,53 @@ func (ts *tradeSource) Err() error {
 	return nil
 }
 
+// LoadTradesConcurrent grava os lotes em paralelo e, depois que todos
+// terminam, recalcula os pregões tocados em daily_aggregations. Os lotes
+// não fazem isso sozinhos porque um mesmo pregão pode estar em vários deles.
+// Se algum lote falha, os que confirmaram continuam gravados, então seus
+// pregões são recalculados antes de devolver o erro.
 func (l *BulkLoader) LoadTradesConcurrent(ctx context.Context, trades []domain.Trade) (int64, error) {
 
 	chunks := l.splitIntoChunks(trades)
 
-	results := make(chan int64, len(chunks))
-	errors := make(chan error, len(chunks))
+	type chunkResult struct {
+		chunk []domain.Trade
+		count int64
+		err   error
+	}
+
+	results := make(chan chunkResult, len(chunks))
 
 	for _, chunk := range chunks {
 		go func(chunk []domain.Trade) {
-			count, err := l.LoadTrades(ctx, chunk)
-			if err != nil {
-				errors <- err
-				return
-			}
-			results <- count
+			count, err := l.loadTrades(ctx, chunk, false)
+			results <- chunkResult{chunk: chunk, count: count, err: err}
 		}(chunk)
 	}
 
 	var totalCount int64
+	var committed []domain.Trade
+	var loadErr error
 	for i := 0; i < len(chunks); i++ {
-		select {
-		case count := <-results:
-			totalCount += count
-		case err := <-errors:
-			return totalCount, err
-		case <-ctx.Done():
-			return totalCount, ctx.Err()
+		result := <-results
+		if result.err != nil {
+			if loadErr == nil {
+				loadErr = result.err
+			}
+			continue
+		}
+		totalCount += result.count
+		committed = append(committed, result.chunk...)
+	}
+
+	if err := refreshSessions(ctx, l.pool, SessionKeys(committed)); err != nil {
+		if loadErr != nil {
+			return totalCount, fmt.Errorf("%w (e ao recalcular agregações: %v)", loadErr, err)
 		}
+		return totalCount, err
 	}
 
-	return totalCount, nil
+	return totalCount, loadErr
 }
 
 func (l *BulkLoader) splitIntoChunks(trades []domain.Trade) [][]domain.Trade {
@@ -4,8 +4,14 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/shopspring/decimal"
 )
 
 func BenchmarkParser(b *testing.B) {
@@ -98,3 +104,35 @@ func generateTestCSV(lines int) string {
 
 	return sb.String()
 }
+
+func generateTestTrades(n int) []domain.Trade {
+	tickers := []string{"PETR4", "VALE3", "ITUB4", "BBDC4"}
+	date := time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC)
+
+	trades := make([]domain.Trade, 0, n)
+	for i := 0; i < n; i++ {
+		trades = append(trades, domain.Trade{
+			HoraFechamento:      time.Date(1900, 1, 1, 10, 0, i%3600, 0, time.UTC),
+			DataNegocio:         date,
+			CodigoInstrumento:   tickers[i%len(tickers)],
+			PrecoNegocio:        decimal.NewFromFloat(float64(20 + i%30)),
+			QuantidadeNegociada: int64(100 + i%1000),
+		})
+	}
+
+	return trades
+}
+
+func setupTestDB(b *testing.B) *pgxpool.Pool {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		b.Skip("TEST_DATABASE_URL não definida")
+	}
+
+	pool, err := pgxpool.New(context.Background(), url)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return pool
+}
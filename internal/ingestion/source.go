@@ -0,0 +1,72 @@
+package ingestion
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// OpenSourceFile abre um arquivo de negócios da B3, aceitando tanto o TXT
+// extraído quanto o ZIP original (neste caso lê o primeiro TXT do arquivo).
+func OpenSourceFile(path string) (io.ReadCloser, error) {
+	if !strings.HasSuffix(strings.ToLower(path), ".zip") {
+		return os.Open(path)
+	}
+
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir ZIP: %w", err)
+	}
+
+	for _, file := range reader.File {
+		if !strings.HasSuffix(strings.ToLower(file.Name), ".txt") {
+			continue
+		}
+
+		fileReader, err := file.Open()
+		if err != nil {
+			reader.Close()
+			return nil, fmt.Errorf("erro ao abrir %s: %w", file.Name, err)
+		}
+
+		return &zipEntryReader{ReadCloser: fileReader, archive: reader}, nil
+	}
+
+	reader.Close()
+	return nil, fmt.Errorf("nenhum arquivo TXT encontrado em %s", path)
+}
+
+// FindSourceFile procura no diretório o arquivo de negócios de uma data,
+// preferindo o TXT extraído ao ZIP.
+func FindSourceFile(dir string, date time.Time) (string, error) {
+	pattern := filepath.Join(dir, fmt.Sprintf("*%s*", date.Format("02-01-2006")))
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	for _, ext := range []string{".txt", ".zip"} {
+		for _, match := range matches {
+			if strings.HasSuffix(strings.ToLower(match), ext) {
+				return match, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("nenhum arquivo encontrado para %s em %s", date.Format("2006-01-02"), dir)
+}
+
+type zipEntryReader struct {
+	io.ReadCloser
+	archive *zip.ReadCloser
+}
+
+func (r *zipEntryReader) Close() error {
+	r.ReadCloser.Close()
+	return r.archive.Close()
+}
@@ -3,8 +3,8 @@ package ingestion
 import (
 	"context"
 	"fmt"
-	"os"
 	"sync"
+	"time"
 )
 
 type WorkerPool struct {
@@ -20,9 +20,12 @@ type Job struct {
 	Result   chan<- JobResult
 }
 
+// JobResult traz os pregões do arquivo mesmo quando a carga falha, já que
+// parte dos lotes pode ter sido gravada.
 type JobResult struct {
 	FilePath     string
 	RecordsCount int64
+	Dates        []time.Time
 	Error        error
 }
 
@@ -72,7 +75,7 @@ func (wp *WorkerPool) worker(ctx context.Context, id int) {
 
 func (wp *WorkerPool) processFile(ctx context.Context, filePath string) JobResult {
 
-	file, err := os.Open(filePath)
+	file, err := OpenSourceFile(filePath)
 	if err != nil {
 		return JobResult{
 			FilePath: filePath,
@@ -89,11 +92,14 @@ func (wp *WorkerPool) processFile(ctx context.Context, filePath string) JobResul
 		}
 	}
 
+	dates := SessionDates(SessionKeys(parseResult.Trades))
+
 	count, err := wp.loader.LoadTradesConcurrent(ctx, parseResult.Trades)
 	if err != nil {
 		return JobResult{
 			FilePath:     filePath,
 			RecordsCount: count,
+			Dates:        dates,
 			Error:        fmt.Errorf("erro ao carregar: %w", err),
 		}
 	}
@@ -101,6 +107,7 @@ func (wp *WorkerPool) processFile(ctx context.Context, filePath string) JobResul
 	return JobResult{
 		FilePath:     filePath,
 		RecordsCount: count,
+		Dates:        dates,
 		Error:        nil,
 	}
 }
@@ -9,6 +9,8 @@ import (
 	"github.com/go-redis/redis/v8"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/internal/ingestion"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
 	"github.com/shopspring/decimal"
 )
 
@@ -87,6 +89,107 @@ func (s *AggregationService) queryAggregation(ctx context.Context, ticker string
 	}, nil
 }
 
+// TickerAggregationResult é o resultado de um ticker no lote: a agregação
+// ou o erro daquele ticker.
+type TickerAggregationResult struct {
+	Ticker      string
+	Aggregation *domain.Aggregation
+	CacheHit    bool
+	Err         error
+}
+
+// GetTickerAggregations resolve um lote de tickers no período
+// [startDate, endDate], ambos opcionais. Os tickers em cache vêm de um único
+// MGET e os demais de uma única consulta; tickers sem pregões no período
+// recebem ErrNotFound. O resultado segue a ordem de tickers.
+func (s *AggregationService) GetTickerAggregations(ctx context.Context, tickers []string, startDate, endDate *time.Time) ([]TickerAggregationResult, error) {
+	results := make([]TickerAggregationResult, len(tickers))
+	keys := make([]string, len(tickers))
+	for i, ticker := range tickers {
+		results[i].Ticker = ticker
+		keys[i] = s.generateRangeCacheKey(ticker, startDate, endDate)
+	}
+
+	var missing []string
+	cached := s.getManyFromCache(ctx, keys)
+	for i := range results {
+		if cached[i] != nil {
+			results[i].Aggregation = cached[i]
+			results[i].CacheHit = true
+			continue
+		}
+		missing = append(missing, results[i].Ticker)
+	}
+
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	found, err := s.queryAggregations(ctx, missing, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar agregações: %w", err)
+	}
+
+	for i := range results {
+		if results[i].CacheHit {
+			continue
+		}
+
+		aggregation, ok := found[results[i].Ticker]
+		if !ok {
+			results[i].Err = ErrNotFound
+			continue
+		}
+
+		results[i].Aggregation = aggregation
+		if err := s.saveToCache(ctx, keys[i], aggregation); err != nil {
+			// Log do erro de cache, mas não falha a operação
+		}
+	}
+
+	return results, nil
+}
+
+func (s *AggregationService) queryAggregations(ctx context.Context, tickers []string, startDate, endDate *time.Time) (map[string]*domain.Aggregation, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("batch_aggregation"))
+
+	query := `
+		SELECT
+			codigo_instrumento,
+			MAX(max_price) as max_range_value,
+			MAX(total_volume) as max_daily_volume
+		FROM daily_aggregations
+		WHERE codigo_instrumento = ANY($1)
+		AND ($2::date IS NULL OR data_negocio >= $2)
+		AND ($3::date IS NULL OR data_negocio <= $3)
+		GROUP BY codigo_instrumento
+	`
+
+	rows, err := s.pool.Query(ctx, query, tickers, startDate, endDate)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("batch_aggregation", "error").Inc()
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]*domain.Aggregation, len(tickers))
+	for rows.Next() {
+		var aggregation domain.Aggregation
+		if err := rows.Scan(&aggregation.Ticker, &aggregation.MaxRangeValue, &aggregation.MaxDailyVolume); err != nil {
+			return nil, err
+		}
+		result[aggregation.Ticker] = &aggregation
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("batch_aggregation", "success").Inc()
+	return result, nil
+}
+
 func (s *AggregationService) generateCacheKey(ticker string, startDate *time.Time) string {
 	if startDate == nil {
 		return fmt.Sprintf("agg:%s:all", ticker)
@@ -94,6 +197,15 @@ func (s *AggregationService) generateCacheKey(ticker string, startDate *time.Tim
 	return fmt.Sprintf("agg:%s:%s", ticker, startDate.Format("2006-01-02"))
 }
 
+// generateRangeCacheKey mantém a chave de generateCacheKey quando não há
+// data final, para que lote e consulta individual compartilhem o cache.
+func (s *AggregationService) generateRangeCacheKey(ticker string, startDate, endDate *time.Time) string {
+	if endDate == nil {
+		return s.generateCacheKey(ticker, startDate)
+	}
+	return fmt.Sprintf("%s:%s", s.generateCacheKey(ticker, startDate), endDate.Format("2006-01-02"))
+}
+
 func (s *AggregationService) getFromCache(ctx context.Context, key string) (*domain.Aggregation, error) {
 	if s.redisClient == nil {
 		return nil, fmt.Errorf("redis not available")
@@ -112,6 +224,34 @@ func (s *AggregationService) getFromCache(ctx context.Context, key string) (*dom
 	return &aggregation, nil
 }
 
+// getManyFromCache devolve, na ordem de keys, as agregações em cache; chaves
+// ausentes, ilegíveis ou um Redis indisponível resultam em nil.
+func (s *AggregationService) getManyFromCache(ctx context.Context, keys []string) []*domain.Aggregation {
+	result := make([]*domain.Aggregation, len(keys))
+	if s.redisClient == nil || len(keys) == 0 {
+		return result
+	}
+
+	values, err := s.redisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		return result
+	}
+
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var aggregation domain.Aggregation
+		if err := json.Unmarshal([]byte(raw), &aggregation); err == nil {
+			result[i] = &aggregation
+		}
+	}
+
+	return result
+}
+
 func (s *AggregationService) saveToCache(ctx context.Context, key string, aggregation *domain.Aggregation) error {
 	if s.redisClient == nil {
 		return nil
@@ -125,7 +265,34 @@ func (s *AggregationService) saveToCache(ctx context.Context, key string, aggreg
 	return s.redisClient.Set(ctx, key, data, s.cacheTTL).Err()
 }
 
-func (s *AggregationService) RefreshMaterializedViews(ctx context.Context) error {
-	_, err := s.pool.Exec(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY daily_aggregations")
-	return err
+// RebuildDailyAggregations refaz daily_aggregations a partir de trades. As
+// cargas já mantêm a tabela pregão a pregão; isto é o reparo completo.
+func (s *AggregationService) RebuildDailyAggregations(ctx context.Context) (int64, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("rebuild_aggregations"))
+
+	rows, err := ingestion.RebuildDailyAggregations(ctx, s.pool)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("rebuild_aggregations", "error").Inc()
+		return 0, err
+	}
+	metrics.DatabaseQueries.WithLabelValues("rebuild_aggregations", "success").Inc()
+
+	s.InvalidateCache(ctx)
+	return rows, nil
+}
+
+// InvalidateCache descarta os resultados em cache derivados dos pregões,
+// que ficam desatualizados após cargas e reconstruções.
+func (s *AggregationService) InvalidateCache(ctx context.Context) {
+	if s.redisClient == nil {
+		return
+	}
+
+	for _, pattern := range []string{"agg:*", "liquidity:*", "candles:*", "corr:*"} {
+		iter := s.redisClient.Scan(ctx, 0, pattern, 0).Iterator()
+		for iter.Next(ctx) {
+			s.redisClient.Del(ctx, iter.Val())
+		}
+	}
 }
@@ -0,0 +1,705 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	AlertCrossesAbove = "crosses_above"
+	AlertCrossesBelow = "crosses_below"
+	AlertVolumeSpike  = "volume_spike"
+	AlertDailyMove    = "daily_move"
+)
+
+const (
+	DeliveryPending    = "pending"
+	DeliveryDelivering = "delivering"
+	DeliveryDelivered  = "delivered"
+	DeliveryFailed     = "failed"
+	DeliverySkipped    = "skipped"
+)
+
+const DefaultAlertLookback = 20
+
+// Limites das colunas alert_rules.name e codigo_instrumento; o de ticker
+// vale para todas as tabelas.
+const (
+	AlertRuleNameMaxLength = 100
+	TickerMaxLength        = 20
+)
+
+// maxDeliveryAttempts limita as tentativas somadas de todas as avaliações;
+// a partir daí o evento fica como failed. Eventos em delivering há mais de
+// claimTimeout são de uma avaliação interrompida e voltam a ser entregues.
+const (
+	maxDeliveryAttempts = 10
+	claimTimeout        = 15 * time.Minute
+)
+
+var alertConditions = map[string]bool{
+	AlertCrossesAbove: true,
+	AlertCrossesBelow: true,
+	AlertVolumeSpike:  true,
+	AlertDailyMove:    true,
+}
+
+type AlertService struct {
+	pool        *pgxpool.Pool
+	client      *http.Client
+	ruleClient  *http.Client
+	webhookURLs []string
+	retries     int
+	retryDelay  time.Duration
+}
+
+func NewAlertService(pool *pgxpool.Pool, webhookURLs []string, retries int, timeout time.Duration) *AlertService {
+	if retries <= 0 {
+		retries = 1
+	}
+
+	return &AlertService{
+		pool:        pool,
+		client:      &http.Client{Timeout: timeout},
+		ruleClient:  publicOnlyClient(timeout),
+		webhookURLs: webhookURLs,
+		retries:     retries,
+		retryDelay:  time.Second,
+	}
+}
+
+func ValidateAlertRule(rule *domain.AlertRule) error {
+	if rule.Ticker == "" {
+		return fmt.Errorf("ticker é obrigatório")
+	}
+	if utf8.RuneCountInString(rule.Ticker) > TickerMaxLength {
+		return fmt.Errorf("ticker deve ter até %d caracteres", TickerMaxLength)
+	}
+	if utf8.RuneCountInString(rule.Name) > AlertRuleNameMaxLength {
+		return fmt.Errorf("name deve ter até %d caracteres", AlertRuleNameMaxLength)
+	}
+	if !alertConditions[rule.Condition] {
+		return fmt.Errorf("condição inválida: %s (use crosses_above, crosses_below, volume_spike ou daily_move)", rule.Condition)
+	}
+	if !rule.Threshold.IsPositive() {
+		return fmt.Errorf("threshold deve ser positivo")
+	}
+	if rule.Lookback < 1 || rule.Lookback > 250 {
+		return fmt.Errorf("lookback deve estar entre 1 e 250")
+	}
+	if rule.WebhookURL != "" {
+		return validateWebhookURL(rule.WebhookURL)
+	}
+	return nil
+}
+
+// validateWebhookURL recusa URLs que apontam para a própria máquina ou para
+// a rede interna. Nomes que resolvem para esses endereços são barrados na
+// conexão, por publicOnlyClient.
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("webhook_url deve ser uma URL http ou https")
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("webhook_url não pode apontar para endereço interno")
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+		return fmt.Errorf("webhook_url não pode apontar para endereço interno")
+	}
+	return nil
+}
+
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast()
+}
+
+// publicOnlyClient é o cliente usado para o webhook_url das regras: o
+// endereço é conferido a cada conexão, já resolvido, o que cobre DNS que
+// aponta para a rede interna e redirecionamentos. As URLs de ALERT_WEBHOOK_URLS
+// vêm da configuração e usam o cliente normal.
+func publicOnlyClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return fmt.Errorf("endereço %s não permitido para webhook", host)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+func (s *AlertService) CreateRule(ctx context.Context, rule domain.AlertRule) (*domain.AlertRule, error) {
+	if rule.Lookback == 0 {
+		rule.Lookback = DefaultAlertLookback
+	}
+	if err := ValidateAlertRule(&rule); err != nil {
+		return nil, err
+	}
+	if rule.Name == "" {
+		rule.Name = fmt.Sprintf("%s %s %s", rule.Ticker, rule.Condition, rule.Threshold)
+	}
+
+	query := `
+        INSERT INTO alert_rules (name, codigo_instrumento, condition, threshold, lookback, webhook_url)
+        VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+        RETURNING id, active, created_at
+    `
+
+	err := s.pool.QueryRow(ctx, query,
+		rule.Name, rule.Ticker, rule.Condition, rule.Threshold, rule.Lookback, rule.WebhookURL,
+	).Scan(&rule.ID, &rule.Active, &rule.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar regra de alerta: %w", err)
+	}
+
+	return &rule, nil
+}
+
+func (s *AlertService) ListRules(ctx context.Context, activeOnly bool) ([]domain.AlertRule, error) {
+	query := `
+        SELECT id, name, codigo_instrumento, condition, threshold, lookback,
+               COALESCE(webhook_url, ''), active, created_at
+        FROM alert_rules
+    `
+	if activeOnly {
+		query += " WHERE active"
+	}
+	query += " ORDER BY id"
+
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar regras de alerta: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []domain.AlertRule{}
+	for rows.Next() {
+		var rule domain.AlertRule
+		err := rows.Scan(
+			&rule.ID,
+			&rule.Name,
+			&rule.Ticker,
+			&rule.Condition,
+			&rule.Threshold,
+			&rule.Lookback,
+			&rule.WebhookURL,
+			&rule.Active,
+			&rule.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear regra de alerta: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar regras de alerta: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (s *AlertService) DeleteRule(ctx context.Context, id int64) error {
+	tag, err := s.pool.Exec(ctx, "DELETE FROM alert_rules WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("erro ao remover regra de alerta: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *AlertService) ListEvents(ctx context.Context, filter domain.AlertEventFilter) ([]domain.AlertEvent, int, error) {
+	where := []string{"TRUE"}
+	var args []interface{}
+
+	if filter.RuleID != nil {
+		args = append(args, *filter.RuleID)
+		where = append(where, fmt.Sprintf("e.rule_id = $%d", len(args)))
+	}
+	if filter.Ticker != "" {
+		args = append(args, filter.Ticker)
+		where = append(where, fmt.Sprintf("e.codigo_instrumento = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where = append(where, fmt.Sprintf("e.delivery_status = $%d", len(args)))
+	}
+
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+
+	query := fmt.Sprintf(`
+        SELECT
+            e.id, e.rule_id, r.name, e.codigo_instrumento, r.condition,
+            e.data_negocio, e.value, r.threshold, e.message, e.triggered_at,
+            e.delivery_status, e.delivery_attempts, e.delivered_at,
+            COALESCE(e.last_error, ''),
+            COUNT(*) OVER () as total_count
+        FROM alert_events e
+        JOIN alert_rules r ON r.id = e.rule_id
+        WHERE %s
+        ORDER BY e.triggered_at DESC, e.id DESC
+        LIMIT $%d OFFSET $%d
+    `, strings.Join(where, " AND "), len(args)-1, len(args))
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao buscar eventos de alerta: %w", err)
+	}
+	defer rows.Close()
+
+	events := []domain.AlertEvent{}
+	total := 0
+	for rows.Next() {
+		var event domain.AlertEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.RuleID,
+			&event.RuleName,
+			&event.Ticker,
+			&event.Condition,
+			&event.Date,
+			&event.Value,
+			&event.Threshold,
+			&event.Message,
+			&event.TriggeredAt,
+			&event.DeliveryStatus,
+			&event.DeliveryAttempts,
+			&event.DeliveredAt,
+			&event.LastError,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("erro ao escanear evento de alerta: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("erro ao iterar eventos de alerta: %w", err)
+	}
+
+	return events, total, nil
+}
+
+// Evaluate avalia as regras ativas no pregão informado (ou no último
+// carregado) e entrega os eventos disparados aos webhooks. Reavaliar o
+// mesmo pregão não duplica eventos nem reenvia os já entregues.
+func (s *AlertService) Evaluate(ctx context.Context, date *time.Time) (*domain.AlertEvaluation, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("alert_evaluation"))
+
+	if date == nil {
+		var latest *time.Time
+		if err := s.pool.QueryRow(ctx, "SELECT MAX(data_negocio) FROM daily_aggregations").Scan(&latest); err != nil {
+			return nil, fmt.Errorf("erro ao buscar último pregão: %w", err)
+		}
+		if latest == nil {
+			return nil, ErrNotFound
+		}
+		date = latest
+	}
+
+	rules, err := s.ListRules(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation := &domain.AlertEvaluation{
+		Date:           *date,
+		RulesEvaluated: len(rules),
+		Fired:          []domain.AlertEvent{},
+	}
+
+	for _, rule := range rules {
+		bars, err := s.queryAlertBars(ctx, rule.Ticker, *date, rule.Lookback+1)
+		if err != nil {
+			return nil, err
+		}
+
+		// a regra só é avaliada se o ticker negociou no pregão
+		if len(bars) == 0 || !bars[len(bars)-1].date.Equal(*date) {
+			continue
+		}
+
+		value, fired, message := evaluateRule(rule, bars)
+		if !fired {
+			continue
+		}
+
+		event, err := s.recordEvent(ctx, rule, *date, value, message)
+		if err != nil {
+			return nil, err
+		}
+		if event != nil {
+			evaluation.Fired = append(evaluation.Fired, *event)
+		}
+	}
+
+	if err := s.deliverPending(ctx); err != nil {
+		return nil, err
+	}
+
+	logger.Info("alertas avaliados",
+		zap.Time("date", *date),
+		zap.Int("rules", len(rules)),
+		zap.Int("fired", len(evaluation.Fired)))
+
+	return evaluation, nil
+}
+
+// EvaluateDates avalia cada pregão informado, em ordem cronológica, para
+// que cargas de vários pregões não percam os disparos dos anteriores ao
+// último. Sem datas, avalia só o último pregão carregado.
+func (s *AlertService) EvaluateDates(ctx context.Context, dates []time.Time) ([]domain.AlertEvaluation, error) {
+	if len(dates) == 0 {
+		evaluation, err := s.Evaluate(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		return []domain.AlertEvaluation{*evaluation}, nil
+	}
+
+	sorted := append([]time.Time(nil), dates...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	var evaluations []domain.AlertEvaluation
+	for i, date := range sorted {
+		if i > 0 && date.Equal(sorted[i-1]) {
+			continue
+		}
+		evaluation, err := s.Evaluate(ctx, &date)
+		if err != nil {
+			return evaluations, err
+		}
+		evaluations = append(evaluations, *evaluation)
+	}
+
+	return evaluations, nil
+}
+
+type alertBar struct {
+	date   time.Time
+	close  float64
+	volume int64
+}
+
+// queryAlertBars retorna os últimos n pregões do ticker até date, em ordem
+// cronológica.
+func (s *AlertService) queryAlertBars(ctx context.Context, ticker string, date time.Time, n int) ([]alertBar, error) {
+	query := `
+        SELECT data_negocio, close_price::float8, total_volume
+        FROM (
+            SELECT data_negocio, close_price, total_volume
+            FROM daily_aggregations
+            WHERE codigo_instrumento = $1
+            AND data_negocio <= $2
+            ORDER BY data_negocio DESC
+            LIMIT $3
+        ) recent
+        ORDER BY data_negocio ASC
+    `
+
+	rows, err := s.pool.Query(ctx, query, ticker, date, n)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pregões para alerta: %w", err)
+	}
+	defer rows.Close()
+
+	var bars []alertBar
+	for rows.Next() {
+		var bar alertBar
+		if err := rows.Scan(&bar.date, &bar.close, &bar.volume); err != nil {
+			return nil, fmt.Errorf("erro ao escanear pregão para alerta: %w", err)
+		}
+		bars = append(bars, bar)
+	}
+
+	return bars, rows.Err()
+}
+
+// evaluateRule avalia a regra no último pregão de bars. Os anteriores
+// servem de referência: fechamento anterior para cruzamentos e variação,
+// média de volume para volume_spike.
+func evaluateRule(rule domain.AlertRule, bars []alertBar) (float64, bool, string) {
+	if len(bars) < 2 {
+		return 0, false, ""
+	}
+
+	current := bars[len(bars)-1]
+	previous := bars[len(bars)-2]
+	threshold := rule.Threshold.InexactFloat64()
+
+	switch rule.Condition {
+	case AlertCrossesAbove:
+		fired := previous.close < threshold && current.close >= threshold
+		return current.close, fired, fmt.Sprintf("%s fechou em %.2f, cruzando %.2f para cima (anterior %.2f)",
+			rule.Ticker, current.close, threshold, previous.close)
+
+	case AlertCrossesBelow:
+		fired := previous.close > threshold && current.close <= threshold
+		return current.close, fired, fmt.Sprintf("%s fechou em %.2f, cruzando %.2f para baixo (anterior %.2f)",
+			rule.Ticker, current.close, threshold, previous.close)
+
+	case AlertVolumeSpike:
+		history := bars[:len(bars)-1]
+		if rule.Lookback > 0 && len(history) > rule.Lookback {
+			history = history[len(history)-rule.Lookback:]
+		}
+
+		var sum int64
+		for _, bar := range history {
+			sum += bar.volume
+		}
+		if sum == 0 {
+			return 0, false, ""
+		}
+
+		ratio := float64(current.volume) / (float64(sum) / float64(len(history)))
+		return ratio, ratio > threshold, fmt.Sprintf("%s negociou %d, %.1fx a média de %d pregões",
+			rule.Ticker, current.volume, ratio, len(history))
+
+	case AlertDailyMove:
+		if previous.close <= 0 {
+			return 0, false, ""
+		}
+
+		change := (current.close/previous.close - 1) * 100
+		return change, math.Abs(change) > threshold, fmt.Sprintf("%s variou %+.2f%% no pregão (%.2f -> %.2f)",
+			rule.Ticker, change, previous.close, current.close)
+	}
+
+	return 0, false, ""
+}
+
+// recordEvent grava o disparo; devolve nil se a regra já disparou no pregão.
+func (s *AlertService) recordEvent(ctx context.Context, rule domain.AlertRule, date time.Time, value float64, message string) (*domain.AlertEvent, error) {
+	event := domain.AlertEvent{
+		RuleID:         rule.ID,
+		RuleName:       rule.Name,
+		Ticker:         rule.Ticker,
+		Condition:      rule.Condition,
+		Date:           date,
+		Value:          value,
+		Threshold:      rule.Threshold,
+		Message:        message,
+		DeliveryStatus: DeliveryPending,
+	}
+
+	query := `
+        INSERT INTO alert_events (rule_id, codigo_instrumento, data_negocio, value, message)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (rule_id, data_negocio) DO NOTHING
+        RETURNING id, triggered_at
+    `
+
+	err := s.pool.QueryRow(ctx, query, rule.ID, rule.Ticker, date, value, message).Scan(&event.ID, &event.TriggeredAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gravar evento de alerta: %w", err)
+	}
+
+	return &event, nil
+}
+
+// deliverPending envia os eventos ainda não entregues, inclusive os que
+// ficaram pendentes ou falharam em avaliações anteriores. Os eventos são
+// marcados como delivering antes do envio, para que avaliações simultâneas
+// não entreguem o mesmo evento duas vezes.
+func (s *AlertService) deliverPending(ctx context.Context) error {
+	query := `
+        WITH claimed AS (
+            UPDATE alert_events
+            SET delivery_status = $1, claimed_at = CURRENT_TIMESTAMP
+            WHERE id IN (
+                SELECT id
+                FROM alert_events
+                WHERE delivery_status = $2
+                   OR (delivery_status = $3 AND delivery_attempts < $4)
+                   OR (delivery_status = $1 AND claimed_at < CURRENT_TIMESTAMP - make_interval(secs => $5))
+                ORDER BY id
+                FOR UPDATE SKIP LOCKED
+            )
+            RETURNING *
+        )
+        SELECT
+            e.id, e.rule_id, r.name, e.codigo_instrumento, r.condition,
+            e.data_negocio, e.value, r.threshold, e.message, e.triggered_at,
+            e.delivery_attempts, COALESCE(r.webhook_url, '')
+        FROM claimed e
+        JOIN alert_rules r ON r.id = e.rule_id
+        ORDER BY e.id
+    `
+
+	rows, err := s.pool.Query(ctx, query,
+		DeliveryDelivering, DeliveryPending, DeliveryFailed, maxDeliveryAttempts, claimTimeout.Seconds())
+	if err != nil {
+		return fmt.Errorf("erro ao buscar eventos pendentes: %w", err)
+	}
+
+	type pendingEvent struct {
+		event      domain.AlertEvent
+		webhookURL string
+	}
+
+	var pending []pendingEvent
+	for rows.Next() {
+		var p pendingEvent
+		err := rows.Scan(
+			&p.event.ID,
+			&p.event.RuleID,
+			&p.event.RuleName,
+			&p.event.Ticker,
+			&p.event.Condition,
+			&p.event.Date,
+			&p.event.Value,
+			&p.event.Threshold,
+			&p.event.Message,
+			&p.event.TriggeredAt,
+			&p.event.DeliveryAttempts,
+			&p.webhookURL,
+		)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("erro ao escanear evento pendente: %w", err)
+		}
+		pending = append(pending, p)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("erro ao iterar eventos pendentes: %w", err)
+	}
+
+	for _, p := range pending {
+		client, urls := s.client, s.webhookURLs
+		if p.webhookURL != "" {
+			client, urls = s.ruleClient, []string{p.webhookURL}
+		}
+
+		status, lastError := DeliverySkipped, ""
+		attempts := 0
+
+		if len(urls) > 0 {
+			attempts, err = s.postWebhooks(ctx, client, urls, p.event)
+			status = DeliveryDelivered
+			if err != nil {
+				status, lastError = DeliveryFailed, err.Error()
+				logger.Warn("falha ao entregar alerta",
+					zap.Int64("event_id", p.event.ID),
+					zap.Int("attempts", attempts),
+					zap.Error(err))
+			}
+		}
+
+		_, err := s.pool.Exec(ctx, `
+            UPDATE alert_events
+            SET delivery_status = $2,
+                delivery_attempts = delivery_attempts + $3,
+                claimed_at = NULL,
+                delivered_at = CASE WHEN $2 = 'delivered' THEN CURRENT_TIMESTAMP END,
+                last_error = NULLIF($4, '')
+            WHERE id = $1
+        `, p.event.ID, status, attempts, lastError)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar entrega do alerta: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// postWebhooks envia o evento para cada URL, repetindo apenas as que
+// falharam com espera exponencial entre as tentativas.
+func (s *AlertService) postWebhooks(ctx context.Context, client *http.Client, urls []string, event domain.AlertEvent) (int, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	remaining := urls
+	var lastErr error
+
+	for attempt := 1; attempt <= s.retries; attempt++ {
+		var failed []string
+		for _, url := range remaining {
+			if err := post(ctx, client, url, payload); err != nil {
+				failed = append(failed, url)
+				lastErr = err
+			}
+		}
+
+		if len(failed) == 0 {
+			return attempt, nil
+		}
+		remaining = failed
+
+		if attempt < s.retries {
+			select {
+			case <-ctx.Done():
+				return attempt, ctx.Err()
+			case <-time.After(s.retryDelay << (attempt - 1)):
+			}
+		}
+	}
+
+	return s.retries, lastErr
+}
+
+func post(ctx context.Context, client *http.Client, url string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("%s: %w", url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "B3-Analyzer")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", url, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: status %d", url, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestEvaluateRule(t *testing.T) {
+	bars := func(closes []float64, volumes []int64) []alertBar {
+		result := make([]alertBar, len(closes))
+		for i := range closes {
+			result[i] = alertBar{close: closes[i], volume: volumes[i]}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		threshold int64
+		bars      []alertBar
+		fired     bool
+	}{
+		{"cruza para cima", AlertCrossesAbove, 40, bars([]float64{39.5, 40.2}, []int64{1, 1}), true},
+		{"já estava acima", AlertCrossesAbove, 40, bars([]float64{40.5, 41}, []int64{1, 1}), false},
+		{"cruza para baixo", AlertCrossesBelow, 40, bars([]float64{40.5, 39.9}, []int64{1, 1}), true},
+		{"volume 3x a média", AlertVolumeSpike, 3, bars([]float64{10, 10, 10}, []int64{100, 100, 350}), true},
+		{"volume abaixo de 3x", AlertVolumeSpike, 3, bars([]float64{10, 10, 10}, []int64{100, 100, 250}), false},
+		{"queda acima de 5%", AlertDailyMove, 5, bars([]float64{100, 94}, []int64{1, 1}), true},
+		{"variação de 3%", AlertDailyMove, 5, bars([]float64{100, 103}, []int64{1, 1}), false},
+		{"sem pregão anterior", AlertDailyMove, 5, bars([]float64{100}, []int64{1}), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := domain.AlertRule{
+				Ticker:    "PETR4",
+				Condition: tt.condition,
+				Threshold: decimal.NewFromInt(tt.threshold),
+				Lookback:  20,
+			}
+
+			_, fired, _ := evaluateRule(rule, tt.bars)
+			if fired != tt.fired {
+				t.Errorf("esperado fired=%v, recebido %v", tt.fired, fired)
+			}
+		})
+	}
+}
+
+func TestPostWebhooksRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	s := NewAlertService(nil, nil, 3, time.Second)
+	s.retryDelay = time.Millisecond
+
+	attempts, err := s.postWebhooks(context.Background(), s.client, []string{server.URL}, domain.AlertEvent{ID: 1})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("esperado 3 tentativas, recebido %d", attempts)
+	}
+
+	atomic.StoreInt32(&calls, -10)
+	s.retries = 2
+
+	if _, err := s.postWebhooks(context.Background(), s.client, []string{server.URL}, domain.AlertEvent{ID: 2}); err == nil {
+		t.Error("esperado erro após esgotar as tentativas")
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://hooks.example.com/alerta", true},
+		{"http://203.0.113.10:8080/hook", true},
+		{"ftp://example.com/hook", false},
+		{"https://", false},
+		{"http://localhost:9000/", false},
+		{"http://127.0.0.1/", false},
+		{"http://10.0.0.5/hook", false},
+		{"http://192.168.1.1/", false},
+		{"http://169.254.169.254/latest/meta-data/", false},
+		{"http://[::1]/", false},
+		{"http://0.0.0.0/", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := validateWebhookURL(tt.url)
+			if (err == nil) != tt.valid {
+				t.Errorf("esperado válido=%v, erro %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestRuleClientRejectsInternalAddresses(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	s := NewAlertService(nil, nil, 1, time.Second)
+
+	if _, err := s.postWebhooks(context.Background(), s.ruleClient, []string{server.URL}, domain.AlertEvent{ID: 1}); err == nil {
+		t.Error("esperado erro ao entregar para endereço de loopback")
+	}
+	if atomic.LoadInt32(&calls) != 0 {
+		t.Errorf("esperado nenhuma requisição, recebido %d", calls)
+	}
+}
+
+func TestValidateAlertRuleLengths(t *testing.T) {
+	rule := func(name, ticker string) *domain.AlertRule {
+		return &domain.AlertRule{
+			Name:      name,
+			Ticker:    ticker,
+			Condition: AlertDailyMove,
+			Threshold: decimal.NewFromInt(5),
+			Lookback:  DefaultAlertLookback,
+		}
+	}
+
+	if err := ValidateAlertRule(rule(strings.Repeat("ç", AlertRuleNameMaxLength), "PETR4")); err != nil {
+		t.Errorf("erro inesperado no limite: %v", err)
+	}
+	if err := ValidateAlertRule(rule(strings.Repeat("a", AlertRuleNameMaxLength+1), "PETR4")); err == nil {
+		t.Error("esperado erro para name acima do limite")
+	}
+	if err := ValidateAlertRule(rule("", strings.Repeat("A", TickerMaxLength+1))); err == nil {
+		t.Error("esperado erro para ticker acima do limite")
+	}
+}
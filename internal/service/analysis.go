@@ -2,17 +2,26 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"math"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeovahfialho/b3-analyzer/internal/storage/cache"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"github.com/jeovahfialho/b3-analyzer/pkg/stats"
 	"github.com/shopspring/decimal"
 )
 
 type AnalysisService struct {
-	pool *pgxpool.Pool
+	pool  *pgxpool.Pool
+	cache *cache.RedisCache
 }
 
-func NewAnalysisService(pool *pgxpool.Pool) *AnalysisService {
-	return &AnalysisService{pool: pool}
+func NewAnalysisService(pool *pgxpool.Pool, cacheService *cache.RedisCache) *AnalysisService {
+	return &AnalysisService{
+		pool:  pool,
+		cache: cacheService,
+	}
 }
 
 type TopVolumeTicker struct {
@@ -20,11 +29,63 @@ type TopVolumeTicker struct {
 	TotalVolume int64           `json:"total_volume"`
 	AvgPrice    decimal.Decimal `json:"avg_price"`
 	TradeCount  int             `json:"trade_count"`
+	Sessions    int             `json:"sessions"`
 }
 
-func (s *AnalysisService) GetTopVolumeTickets(ctx context.Context, limit int) ([]TopVolumeTicker, error) {
+// GetTopVolumeTickets ranqueia os tickers pelo volume somado nos últimos
+// `days` pregões carregados. AvgPrice é o VWAP do período.
+func (s *AnalysisService) GetTopVolumeTickets(ctx context.Context, limit, days int) ([]TopVolumeTicker, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("top_volume"))
 
-	return []TopVolumeTicker{}, nil
+	query := `
+        WITH sessions AS (
+            SELECT DISTINCT data_negocio
+            FROM daily_aggregations
+            ORDER BY data_negocio DESC
+            LIMIT $2
+        )
+        SELECT
+            codigo_instrumento,
+            SUM(total_volume) as total_volume,
+            SUM(financial_volume) / NULLIF(SUM(total_volume), 0) as avg_price,
+            SUM(trade_count) as trade_count,
+            COUNT(*) as sessions
+        FROM daily_aggregations
+        WHERE data_negocio IN (SELECT data_negocio FROM sessions)
+        GROUP BY codigo_instrumento
+        ORDER BY total_volume DESC
+        LIMIT $1
+    `
+
+	rows, err := s.pool.Query(ctx, query, limit, days)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("top_volume", "error").Inc()
+		return nil, fmt.Errorf("erro ao buscar top volume: %w", err)
+	}
+	defer rows.Close()
+
+	result := []TopVolumeTicker{}
+	for rows.Next() {
+		var item TopVolumeTicker
+		var avgPrice *decimal.Decimal
+
+		if err := rows.Scan(&item.Ticker, &item.TotalVolume, &avgPrice, &item.TradeCount, &item.Sessions); err != nil {
+			return nil, fmt.Errorf("erro ao escanear top volume: %w", err)
+		}
+		if avgPrice != nil {
+			item.AvgPrice = avgPrice.Round(4)
+		}
+
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar top volume: %w", err)
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("top_volume", "success").Inc()
+	return result, nil
 }
 
 type PriceRangeResult struct {
@@ -33,31 +94,136 @@ type PriceRangeResult struct {
 	MaxPrice     decimal.Decimal `json:"max_price"`
 	Range        decimal.Decimal `json:"range"`
 	RangePercent float64         `json:"range_percent"`
+	Sessions     int             `json:"sessions"`
 }
 
 func (s *AnalysisService) GetPriceRange(ctx context.Context, ticker string, days int) (*PriceRangeResult, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("price_range"))
 
-	return &PriceRangeResult{
-		Ticker:   ticker,
-		MinPrice: decimal.NewFromFloat(10.0),
-		MaxPrice: decimal.NewFromFloat(20.0),
-		Range:    decimal.NewFromFloat(10.0),
-	}, nil
+	query := `
+        WITH recent AS (
+            SELECT min_price, max_price
+            FROM daily_aggregations
+            WHERE codigo_instrumento = $1
+            ORDER BY data_negocio DESC
+            LIMIT $2
+        )
+        SELECT MIN(min_price), MAX(max_price), COUNT(*)
+        FROM recent
+    `
+
+	var minPrice, maxPrice *decimal.Decimal
+	result := PriceRangeResult{Ticker: ticker}
+
+	err := s.pool.QueryRow(ctx, query, ticker, days).Scan(&minPrice, &maxPrice, &result.Sessions)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("price_range", "error").Inc()
+		return nil, fmt.Errorf("erro ao buscar range de preços: %w", err)
+	}
+
+	if result.Sessions == 0 || minPrice == nil || maxPrice == nil {
+		return nil, ErrNotFound
+	}
+
+	result.MinPrice = *minPrice
+	result.MaxPrice = *maxPrice
+	result.Range = maxPrice.Sub(*minPrice)
+	if minPrice.IsPositive() {
+		result.RangePercent = result.Range.Div(*minPrice).InexactFloat64() * 100
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("price_range", "success").Inc()
+	return &result, nil
 }
 
 type VolatilityResult struct {
 	Ticker       string  `json:"ticker"`
+	Method       string  `json:"method"`
 	Volatility   float64 `json:"volatility"`
 	StdDev       float64 `json:"std_dev"`
 	DaysAnalyzed int     `json:"days_analyzed"`
+	Sessions     int     `json:"sessions"`
 }
 
-func (s *AnalysisService) GetVolatility(ctx context.Context, ticker string, days int) (*VolatilityResult, error) {
+// GetVolatility calcula a volatilidade anualizada (252 pregões) dos últimos
+// `days` pregões. StdDev é a volatilidade diária antes da anualização.
+func (s *AnalysisService) GetVolatility(ctx context.Context, ticker string, days int, method string) (*VolatilityResult, error) {
+	if !stats.ValidVolatilityMethod(method) {
+		return nil, fmt.Errorf("método de volatilidade inválido: %s", method)
+	}
+
+	// o método close precisa do fechamento anterior à janela
+	sessions := days
+	if method == stats.VolatilityCloseToClose {
+		sessions++
+	}
+
+	bars, err := s.getRecentBars(ctx, ticker, sessions)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bars) < 2 {
+		return nil, ErrNotFound
+	}
+
+	daily, used, err := stats.DailyVolatility(bars, method)
+	if err != nil {
+		return nil, err
+	}
+
+	if math.IsNaN(daily) {
+		return nil, ErrNotFound
+	}
 
 	return &VolatilityResult{
 		Ticker:       ticker,
-		Volatility:   15.5,
-		StdDev:       2.3,
+		Method:       method,
+		Volatility:   stats.Annualize(daily),
+		StdDev:       daily,
 		DaysAnalyzed: days,
+		Sessions:     used,
 	}, nil
 }
+
+// getRecentBars retorna os últimos n pregões do ticker em ordem cronológica.
+func (s *AnalysisService) getRecentBars(ctx context.Context, ticker string, n int) ([]stats.OHLC, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("recent_bars"))
+
+	query := `
+        SELECT open_price, max_price, min_price, close_price
+        FROM (
+            SELECT data_negocio, open_price, max_price, min_price, close_price
+            FROM daily_aggregations
+            WHERE codigo_instrumento = $1
+            ORDER BY data_negocio DESC
+            LIMIT $2
+        ) recent
+        ORDER BY data_negocio ASC
+    `
+
+	rows, err := s.pool.Query(ctx, query, ticker, n)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("recent_bars", "error").Inc()
+		return nil, fmt.Errorf("erro ao buscar pregões: %w", err)
+	}
+	defer rows.Close()
+
+	var bars []stats.OHLC
+	for rows.Next() {
+		var bar stats.OHLC
+		if err := rows.Scan(&bar.Open, &bar.High, &bar.Low, &bar.Close); err != nil {
+			return nil, fmt.Errorf("erro ao escanear pregão: %w", err)
+		}
+		bars = append(bars, bar)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar pregões: %w", err)
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("recent_bars", "success").Inc()
+	return bars, nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/pkg/backtest"
+)
+
+// RunBacktest carrega os pregões dos tickers entre start e end, com os
+// pregões anteriores necessários para aquecer os indicadores, e simula a
+// estratégia.
+func (s *AnalysisService) RunBacktest(ctx context.Context, tickers []string, start, end time.Time, strategy backtest.Strategy) (*domain.BacktestResult, error) {
+	strategy = strategy.WithDefaults(len(tickers))
+	if err := strategy.Validate(); err != nil {
+		return nil, err
+	}
+
+	lookback := strategy.Lookback()
+	series := make(map[string]backtest.Series, len(tickers))
+
+	var missing []string
+	for _, ticker := range tickers {
+		bars, first, err := queryDailyBars(ctx, s.pool, ticker, &start, &end, lookback)
+		if err != nil {
+			return nil, err
+		}
+		if first >= len(bars) {
+			missing = append(missing, ticker)
+			continue
+		}
+		series[ticker] = backtest.Series{Bars: bars, Start: first}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, strings.Join(missing, ", "))
+	}
+
+	result, err := backtest.Run(strategy, series)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.BacktestResult{
+		Tickers:   tickers,
+		StartDate: start,
+		EndDate:   end,
+		Result:    *result,
+	}, nil
+}
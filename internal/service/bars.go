@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeovahfialho/b3-analyzer/pkg/indicators"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+)
+
+// queryDailyBars retorna os pregões do ticker entre start e end (ambos
+// opcionais) em ordem cronológica, precedidos de até `lookback` pregões
+// anteriores a start para aquecimento de indicadores. O segundo retorno é
+// o índice do primeiro pregão dentro da janela.
+func queryDailyBars(ctx context.Context, pool *pgxpool.Pool, ticker string, start, end *time.Time, lookback int) ([]indicators.Bar, int, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("daily_bars"))
+
+	const columns = `data_negocio, open_price, max_price, min_price, close_price, total_volume`
+
+	args := []interface{}{ticker}
+	window := fmt.Sprintf(`SELECT %s FROM daily_aggregations WHERE codigo_instrumento = $1`, columns)
+
+	if start != nil {
+		args = append(args, *start)
+		window += fmt.Sprintf(" AND data_negocio >= $%d", len(args))
+	}
+	if end != nil {
+		args = append(args, *end)
+		window += fmt.Sprintf(" AND data_negocio <= $%d", len(args))
+	}
+
+	query := window
+	if start != nil && lookback > 0 {
+		args = append(args, lookback)
+		query = fmt.Sprintf(`
+            SELECT %s FROM (
+                (SELECT %s FROM daily_aggregations
+                 WHERE codigo_instrumento = $1 AND data_negocio < $2
+                 ORDER BY data_negocio DESC
+                 LIMIT $%d)
+                UNION ALL
+                (%s)
+            ) bars`, columns, columns, len(args), window)
+	}
+	query += " ORDER BY data_negocio ASC"
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("daily_bars", "error").Inc()
+		return nil, 0, fmt.Errorf("erro ao buscar pregões: %w", err)
+	}
+	defer rows.Close()
+
+	var bars []indicators.Bar
+	first := 0
+	for rows.Next() {
+		var bar indicators.Bar
+		if err := rows.Scan(&bar.Date, &bar.Open, &bar.High, &bar.Low, &bar.Close, &bar.Volume); err != nil {
+			return nil, 0, fmt.Errorf("erro ao escanear pregão: %w", err)
+		}
+		if start != nil && bar.Date.Before(*start) {
+			first++
+		}
+		bars = append(bars, bar)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("erro ao iterar pregões: %w", err)
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("daily_bars", "success").Inc()
+	return bars, first, nil
+}
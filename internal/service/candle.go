@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/internal/storage/cache"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	CandleFillNone     = "none"
+	CandleFillPrevious = "previous"
+)
+
+var candleIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"60m": time.Hour,
+}
+
+func ParseCandleInterval(interval string) (time.Duration, error) {
+	step, ok := candleIntervals[interval]
+	if !ok {
+		return 0, fmt.Errorf("intervalo inválido: %s (use 1m, 5m, 15m, 30m ou 60m)", interval)
+	}
+	return step, nil
+}
+
+type CandleService struct {
+	pool            *pgxpool.Pool
+	cache           *cache.RedisCache
+	defaultFill     string
+	cachedIntervals map[string]bool
+}
+
+func NewCandleService(pool *pgxpool.Pool, cacheService *cache.RedisCache, defaultFill string, cachedIntervals []string) *CandleService {
+	cached := make(map[string]bool, len(cachedIntervals))
+	for _, interval := range cachedIntervals {
+		cached[interval] = true
+	}
+
+	return &CandleService{
+		pool:            pool,
+		cache:           cacheService,
+		defaultFill:     defaultFill,
+		cachedIntervals: cached,
+	}
+}
+
+func (s *CandleService) GetCandles(ctx context.Context, ticker string, startDate, endDate time.Time, interval, fill string) ([]domain.Candle, error) {
+	step, err := ParseCandleInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	if fill == "" {
+		fill = s.defaultFill
+	}
+	if fill != CandleFillNone && fill != CandleFillPrevious {
+		return nil, fmt.Errorf("fill inválido: %s (use none ou previous)", fill)
+	}
+
+	cacheKey := fmt.Sprintf("candles:%s:%s:%s:%s:%s",
+		ticker, interval, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"), fill)
+	useCache := s.cache != nil && s.cachedIntervals[interval]
+
+	if useCache {
+		var cached []domain.Candle
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			metrics.RecordCacheHit()
+			return cached, nil
+		}
+		metrics.RecordCacheMiss()
+	}
+
+	candles, err := s.QueryCandles(ctx, ticker, startDate, endDate, step)
+	if err != nil {
+		return nil, err
+	}
+
+	if fill == CandleFillPrevious {
+		candles = fillCandles(candles, step)
+	}
+
+	if useCache {
+		if err := s.cache.Set(ctx, cacheKey, candles); err != nil {
+			logger.Warn("erro ao salvar candles no cache", zap.Error(err))
+		}
+	}
+
+	return candles, nil
+}
+
+// QueryCandles agrupa os negócios em janelas de tamanho step, sem
+// preencher janelas vazias.
+func (s *CandleService) QueryCandles(ctx context.Context, ticker string, startDate, endDate time.Time, step time.Duration) ([]domain.Candle, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("candles"))
+
+	query := `
+        WITH bucketed AS (
+            SELECT
+                data_negocio,
+                (FLOOR(EXTRACT(EPOCH FROM hora_fechamento) / $4) * $4)::bigint as bucket,
+                hora_fechamento,
+                id,
+                preco_negocio,
+                quantidade_negociada
+            FROM trades
+            WHERE codigo_instrumento = $1
+            AND data_negocio BETWEEN $2 AND $3
+        )
+        SELECT
+            data_negocio,
+            bucket,
+            (ARRAY_AGG(preco_negocio ORDER BY hora_fechamento, id))[1] as open_price,
+            MAX(preco_negocio) as high_price,
+            MIN(preco_negocio) as low_price,
+            (ARRAY_AGG(preco_negocio ORDER BY hora_fechamento DESC, id DESC))[1] as close_price,
+            SUM(quantidade_negociada) as volume,
+            SUM(preco_negocio * quantidade_negociada) as financial_volume,
+            COUNT(*) as trade_count
+        FROM bucketed
+        GROUP BY data_negocio, bucket
+        ORDER BY data_negocio, bucket
+    `
+
+	rows, err := s.pool.Query(ctx, query, ticker, startDate, endDate, int64(step.Seconds()))
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("candles", "error").Inc()
+		return nil, fmt.Errorf("erro ao buscar candles: %w", err)
+	}
+	defer rows.Close()
+
+	candles := []domain.Candle{}
+	for rows.Next() {
+		var candle domain.Candle
+		var date time.Time
+		var bucket int64
+
+		err := rows.Scan(
+			&date,
+			&bucket,
+			&candle.Open,
+			&candle.High,
+			&candle.Low,
+			&candle.Close,
+			&candle.Volume,
+			&candle.FinancialVolume,
+			&candle.TradeCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear candle: %w", err)
+		}
+
+		candle.Time = date.Add(time.Duration(bucket) * time.Second)
+		candles = append(candles, candle)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar candles: %w", err)
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("candles", "success").Inc()
+	return candles, nil
+}
+
+// fillCandles insere candles sem negócios entre o primeiro e o último
+// candle de cada pregão, repetindo o fechamento anterior.
+func fillCandles(candles []domain.Candle, step time.Duration) []domain.Candle {
+	if len(candles) == 0 {
+		return candles
+	}
+
+	filled := make([]domain.Candle, 0, len(candles))
+	filled = append(filled, candles[0])
+
+	for _, candle := range candles[1:] {
+		prev := filled[len(filled)-1]
+
+		if sameDay(prev.Time, candle.Time) {
+			for t := prev.Time.Add(step); t.Before(candle.Time); t = t.Add(step) {
+				filled = append(filled, domain.Candle{
+					Time:   t,
+					Open:   prev.Close,
+					High:   prev.Close,
+					Low:    prev.Close,
+					Close:  prev.Close,
+					Filled: true,
+				})
+			}
+		}
+
+		filled = append(filled, candle)
+	}
+
+	return filled
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
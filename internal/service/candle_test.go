@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestFillCandles(t *testing.T) {
+	day := time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+
+	candles := []domain.Candle{
+		{Time: day.Add(10 * time.Hour), Close: decimal.NewFromInt(10), Volume: 100},
+		{Time: day.Add(10*time.Hour + 15*time.Minute), Close: decimal.NewFromInt(11), Volume: 200},
+		{Time: nextDay.Add(10 * time.Hour), Close: decimal.NewFromInt(12), Volume: 300},
+	}
+
+	filled := fillCandles(candles, 5*time.Minute)
+
+	if len(filled) != 5 {
+		t.Fatalf("esperado 5 candles, recebido %d", len(filled))
+	}
+
+	for _, i := range []int{1, 2} {
+		if !filled[i].Filled {
+			t.Errorf("candle %d deveria ser preenchido", i)
+		}
+		if !filled[i].Close.Equal(decimal.NewFromInt(10)) || filled[i].Volume != 0 {
+			t.Errorf("candle %d deveria repetir o fechamento anterior sem volume", i)
+		}
+	}
+
+	if filled[4].Filled || !filled[4].Time.Equal(nextDay.Add(10*time.Hour)) {
+		t.Errorf("não deveria preencher entre pregões diferentes")
+	}
+}
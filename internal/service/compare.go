@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	PeriodMTD      = "mtd"
+	PeriodQTD      = "qtd"
+	PeriodYTD      = "ytd"
+	PeriodPrevious = "prev_period"
+	PeriodPrevYear = "prev_year"
+	PeriodCustom   = "custom"
+)
+
+// ResolveComparePeriods monta os períodos A e B. A vem das datas informadas
+// ou de um atalho (mtd, qtd, ytd) terminando em ref. B vem das datas
+// informadas ou de um atalho: prev_period (padrão) é o mês, trimestre ou
+// ano anterior até o mesmo ponto, ou, para datas explícitas, o intervalo de
+// mesma duração imediatamente anterior; prev_year desloca A em um ano.
+func ResolveComparePeriods(ref time.Time, a, b string, aStart, aEnd, bStart, bEnd *time.Time) (domain.StatsPeriod, domain.StatsPeriod, error) {
+	var periodA, periodB domain.StatsPeriod
+
+	switch {
+	case aStart != nil && aEnd != nil:
+		periodA = domain.StatsPeriod{Name: PeriodCustom, StartDate: *aStart, EndDate: *aEnd}
+	case aStart != nil || aEnd != nil:
+		return periodA, periodB, fmt.Errorf("informe a_start e a_end juntos")
+	case a == PeriodMTD:
+		periodA = domain.StatsPeriod{Name: a, StartDate: time.Date(ref.Year(), ref.Month(), 1, 0, 0, 0, 0, time.UTC), EndDate: ref}
+	case a == PeriodQTD:
+		month := time.Month((int(ref.Month())-1)/3*3 + 1)
+		periodA = domain.StatsPeriod{Name: a, StartDate: time.Date(ref.Year(), month, 1, 0, 0, 0, 0, time.UTC), EndDate: ref}
+	case a == PeriodYTD:
+		periodA = domain.StatsPeriod{Name: a, StartDate: time.Date(ref.Year(), 1, 1, 0, 0, 0, 0, time.UTC), EndDate: ref}
+	default:
+		return periodA, periodB, fmt.Errorf("período A inválido: %q (use a_start/a_end ou a=mtd, qtd, ytd)", a)
+	}
+
+	if periodA.EndDate.Before(periodA.StartDate) {
+		return periodA, periodB, fmt.Errorf("a_end anterior a a_start")
+	}
+
+	switch {
+	case bStart != nil && bEnd != nil:
+		periodB = domain.StatsPeriod{Name: PeriodCustom, StartDate: *bStart, EndDate: *bEnd}
+	case bStart != nil || bEnd != nil:
+		return periodA, periodB, fmt.Errorf("informe b_start e b_end juntos")
+	case b == PeriodPrevYear:
+		periodB = domain.StatsPeriod{Name: b, StartDate: shiftMonths(periodA.StartDate, -12), EndDate: shiftMonths(periodA.EndDate, -12)}
+	case b == "" || b == PeriodPrevious:
+		periodB = domain.StatsPeriod{Name: PeriodPrevious}
+		switch periodA.Name {
+		case PeriodMTD, PeriodQTD, PeriodYTD:
+			months := map[string]int{PeriodMTD: 1, PeriodQTD: 3, PeriodYTD: 12}[periodA.Name]
+			periodB.StartDate = shiftMonths(periodA.StartDate, -months)
+			periodB.EndDate = shiftMonths(periodA.EndDate, -months)
+		default:
+			days := int(periodA.EndDate.Sub(periodA.StartDate).Hours()/24) + 1
+			periodB.EndDate = periodA.StartDate.AddDate(0, 0, -1)
+			periodB.StartDate = periodB.EndDate.AddDate(0, 0, -(days - 1))
+		}
+	default:
+		return periodA, periodB, fmt.Errorf("período B inválido: %q (use b_start/b_end ou b=prev_period, prev_year)", b)
+	}
+
+	if periodB.EndDate.Before(periodB.StartDate) {
+		return periodA, periodB, fmt.Errorf("b_end anterior a b_start")
+	}
+
+	return periodA, periodB, nil
+}
+
+// shiftMonths desloca t em n meses limitando o dia ao fim do mês de
+// destino, de modo que 31/03 menos um mês seja 29/02 ou 28/02.
+func shiftMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()).AddDate(0, n, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, t.Location())
+}
+
+// CompareTickerStats calcula TickerStats nos dois períodos e as variações
+// de A em relação a B. Um período sem pregões fica sem stats e sem deltas;
+// sem pregões em nenhum dos dois, devolve ErrNotFound.
+func (s *TradeService) CompareTickerStats(ctx context.Context, ticker string, a, b domain.StatsPeriod) (*domain.TickerStatsComparison, error) {
+	result := &domain.TickerStatsComparison{Ticker: ticker, A: a, B: b}
+
+	var err error
+	result.A.Stats, err = s.GetTickerStatsRange(ctx, ticker, a.StartDate, a.EndDate)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	result.B.Stats, err = s.GetTickerStatsRange(ctx, ticker, b.StartDate, b.EndDate)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	if result.A.Stats == nil && result.B.Stats == nil {
+		return nil, ErrNotFound
+	}
+
+	if result.A.Stats != nil && result.B.Stats != nil {
+		result.Deltas = statsDeltas(result.A.Stats, result.B.Stats)
+	}
+
+	return result, nil
+}
+
+func statsDeltas(a, b *domain.TickerStats) map[string]domain.MetricDelta {
+	pairs := map[string][2]decimal.Decimal{
+		"total_volume":     {decimal.NewFromInt(a.TotalVolume), decimal.NewFromInt(b.TotalVolume)},
+		"total_trades":     {decimal.NewFromInt(int64(a.TotalTrades)), decimal.NewFromInt(int64(b.TotalTrades))},
+		"avg_daily_volume": {decimal.NewFromInt(a.AvgDailyVolume), decimal.NewFromInt(b.AvgDailyVolume)},
+		"avg_price":        {a.AvgPrice, b.AvgPrice},
+		"min_price":        {a.MinPrice, b.MinPrice},
+		"max_price":        {a.MaxPrice, b.MaxPrice},
+		"price_range":      {a.PriceRange, b.PriceRange},
+		"open_price":       {a.OpenPrice, b.OpenPrice},
+		"close_price":      {a.ClosePrice, b.ClosePrice},
+		"vwap":             {a.VWAP, b.VWAP},
+		"financial_volume": {a.FinancialVolume, b.FinancialVolume},
+		"volatility":       {decimal.NewFromFloat(a.Volatility), decimal.NewFromFloat(b.Volatility)},
+		"days_traded":      {decimal.NewFromInt(int64(a.DaysTraded)), decimal.NewFromInt(int64(b.DaysTraded))},
+	}
+
+	deltas := make(map[string]domain.MetricDelta, len(pairs))
+	for name, pair := range pairs {
+		delta := domain.MetricDelta{A: pair[0], B: pair[1], Change: pair[0].Sub(pair[1])}
+		if !pair[1].IsZero() {
+			percent := delta.Change.Div(pair[1]).InexactFloat64() * 100
+			delta.ChangePercent = &percent
+		}
+		deltas[name] = delta
+	}
+
+	return deltas
+}
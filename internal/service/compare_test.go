@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveComparePeriods(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	ref := day(2024, 3, 31)
+
+	tests := []struct {
+		name         string
+		a, b         string
+		aStart, aEnd *time.Time
+		wantA, wantB [2]time.Time
+	}{
+		{"mtd contra mês anterior", PeriodMTD, "", nil, nil,
+			[2]time.Time{day(2024, 3, 1), ref}, [2]time.Time{day(2024, 2, 1), day(2024, 2, 29)}},
+		{"qtd contra trimestre anterior", PeriodQTD, "", nil, nil,
+			[2]time.Time{day(2024, 1, 1), ref}, [2]time.Time{day(2023, 10, 1), day(2023, 12, 31)}},
+		{"ytd contra ano anterior", PeriodYTD, PeriodPrevYear, nil, nil,
+			[2]time.Time{day(2024, 1, 1), ref}, [2]time.Time{day(2023, 1, 1), day(2023, 3, 31)}},
+		{"datas explícitas contra intervalo anterior", "", "", ptrTime(day(2024, 3, 11)), ptrTime(day(2024, 3, 20)),
+			[2]time.Time{day(2024, 3, 11), day(2024, 3, 20)}, [2]time.Time{day(2024, 3, 1), day(2024, 3, 10)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b, err := ResolveComparePeriods(ref, tt.a, tt.b, tt.aStart, tt.aEnd, nil, nil)
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if !a.StartDate.Equal(tt.wantA[0]) || !a.EndDate.Equal(tt.wantA[1]) {
+				t.Errorf("período A: esperado %v, recebido %v a %v", tt.wantA, a.StartDate, a.EndDate)
+			}
+			if !b.StartDate.Equal(tt.wantB[0]) || !b.EndDate.Equal(tt.wantB[1]) {
+				t.Errorf("período B: esperado %v, recebido %v a %v", tt.wantB, b.StartDate, b.EndDate)
+			}
+		})
+	}
+
+	if _, _, err := ResolveComparePeriods(ref, "wtd", "", nil, nil, nil, nil); err == nil {
+		t.Error("esperado erro para atalho inválido")
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"github.com/jeovahfialho/b3-analyzer/pkg/stats"
+	"go.uber.org/zap"
+)
+
+const minCorrelationObservations = 3
+
+// GetCorrelation calcula a matriz de correlação dos retornos dos tickers,
+// alinhados nos períodos em que todos (inclusive o benchmark) negociaram, e
+// o beta de cada ticker contra o benchmark. O resultado fica em cache pelo
+// hash da consulta.
+func (s *AnalysisService) GetCorrelation(ctx context.Context, q domain.CorrelationQuery) (*domain.CorrelationResult, error) {
+	cacheKey := "corr:" + correlationHash(q)
+
+	if s.cache != nil {
+		var cached domain.CorrelationResult
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			metrics.RecordCacheHit()
+			cached.CacheHit = true
+			return &cached, nil
+		}
+		metrics.RecordCacheMiss()
+	}
+
+	all := q.Tickers
+	if q.Benchmark != "" && !containsString(q.Tickers, q.Benchmark) {
+		all = append(append([]string{}, q.Tickers...), q.Benchmark)
+	}
+
+	closes, err := s.queryCloses(ctx, all, q.StartDate, q.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, ticker := range all {
+		if len(closes[ticker]) == 0 {
+			missing = append(missing, ticker)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, strings.Join(missing, ", "))
+	}
+
+	returns, observations := alignedReturns(closes, all, q.Frequency)
+	if observations < minCorrelationObservations {
+		return nil, fmt.Errorf("%w: apenas %d retornos em comum", ErrNotFound, observations)
+	}
+
+	result := &domain.CorrelationResult{
+		Tickers:      q.Tickers,
+		Benchmark:    q.Benchmark,
+		StartDate:    q.StartDate,
+		EndDate:      q.EndDate,
+		Frequency:    q.Frequency,
+		Observations: observations,
+		Pearson:      correlationMatrix(q.Tickers, returns, stats.Pearson),
+	}
+
+	if q.Spearman {
+		result.Spearman = correlationMatrix(q.Tickers, returns, stats.Spearman)
+	}
+
+	if q.Benchmark != "" {
+		benchmark := returns[q.Benchmark]
+		for _, ticker := range q.Tickers {
+			result.Betas = append(result.Betas, domain.TickerBeta{
+				Ticker:      ticker,
+				Beta:        nullableFloat(stats.Beta(benchmark, returns[ticker])),
+				Correlation: nullableFloat(stats.Pearson(benchmark, returns[ticker])),
+			})
+		}
+	}
+
+	if s.cache != nil {
+		if err := s.cache.Set(ctx, cacheKey, result); err != nil {
+			logger.Warn("erro ao salvar correlação no cache", zap.Error(err))
+		}
+	}
+
+	return result, nil
+}
+
+func (s *AnalysisService) queryCloses(ctx context.Context, tickers []string, start, end time.Time) (map[string][]datedValue, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("closes"))
+
+	query := `
+        SELECT codigo_instrumento, data_negocio, close_price
+        FROM daily_aggregations
+        WHERE codigo_instrumento = ANY($1)
+        AND data_negocio BETWEEN $2 AND $3
+        ORDER BY codigo_instrumento, data_negocio
+    `
+
+	rows, err := s.pool.Query(ctx, query, tickers, start, end)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("closes", "error").Inc()
+		return nil, fmt.Errorf("erro ao buscar fechamentos: %w", err)
+	}
+	defer rows.Close()
+
+	closes := make(map[string][]datedValue, len(tickers))
+	for rows.Next() {
+		var ticker string
+		var v datedValue
+		if err := rows.Scan(&ticker, &v.Date, &v.Value); err != nil {
+			return nil, fmt.Errorf("erro ao escanear fechamento: %w", err)
+		}
+		closes[ticker] = append(closes[ticker], v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar fechamentos: %w", err)
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("closes", "success").Inc()
+	return closes, nil
+}
+
+// alignedReturns reamostra os fechamentos, mantém apenas os períodos
+// comuns a todos os tickers e calcula os retornos simples entre eles.
+func alignedReturns(closes map[string][]datedValue, tickers []string, freq string) (map[string][]float64, int) {
+	byPeriod := make(map[string]map[string]float64, len(tickers))
+	counts := make(map[string]int)
+
+	for _, ticker := range tickers {
+		periods := make(map[string]float64)
+		for _, v := range resampleLast(closes[ticker], freq) {
+			key := periodKey(v.Date, freq)
+			periods[key] = v.Value
+			counts[key]++
+		}
+		byPeriod[ticker] = periods
+	}
+
+	var common []string
+	for key, count := range counts {
+		if count == len(tickers) {
+			common = append(common, key)
+		}
+	}
+	sort.Strings(common)
+
+	returns := make(map[string][]float64, len(tickers))
+	for _, ticker := range tickers {
+		prices := make([]float64, len(common))
+		for i, key := range common {
+			prices[i] = byPeriod[ticker][key]
+		}
+		returns[ticker] = stats.SimpleReturns(prices)
+	}
+
+	observations := 0
+	if len(common) > 1 {
+		observations = len(common) - 1
+	}
+	return returns, observations
+}
+
+func correlationMatrix(tickers []string, returns map[string][]float64, fn func(x, y []float64) float64) [][]*float64 {
+	matrix := make([][]*float64, len(tickers))
+	for i := range tickers {
+		matrix[i] = make([]*float64, len(tickers))
+	}
+
+	for i, a := range tickers {
+		for j := i; j < len(tickers); j++ {
+			value := nullableFloat(fn(returns[a], returns[tickers[j]]))
+			matrix[i][j] = value
+			matrix[j][i] = value
+		}
+	}
+	return matrix
+}
+
+func correlationHash(q domain.CorrelationQuery) string {
+	data, _ := json.Marshal(q)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func nullableFloat(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
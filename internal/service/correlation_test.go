@@ -0,0 +1,39 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAlignedReturns(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 5, d, 0, 0, 0, 0, time.UTC) }
+
+	closes := map[string][]datedValue{
+		"PETR4": {{day(19), 10}, {day(20), 11}, {day(21), 12}, {day(22), 12}},
+		"VALE3": {{day(19), 50}, {day(21), 55}, {day(22), 66}},
+	}
+
+	returns, observations := alignedReturns(closes, []string{"PETR4", "VALE3"}, FrequencyDaily)
+
+	// 20/05 fica de fora por falta de negócio em VALE3
+	if observations != 2 {
+		t.Fatalf("esperado 2 retornos em comum, recebido %d", observations)
+	}
+	if math.Abs(returns["PETR4"][0]-0.2) > 1e-9 || math.Abs(returns["VALE3"][1]-0.2) > 1e-9 {
+		t.Errorf("retornos incorretos: %v", returns)
+	}
+}
+
+func TestResampleLast(t *testing.T) {
+	values := []datedValue{
+		{time.Date(2025, 5, 29, 0, 0, 0, 0, time.UTC), 1},
+		{time.Date(2025, 5, 30, 0, 0, 0, 0, time.UTC), 2},
+		{time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), 3},
+	}
+
+	monthly := resampleLast(values, FrequencyMonthly)
+	if len(monthly) != 2 || monthly[0].Value != 2 || monthly[1].Value != 3 {
+		t.Errorf("reamostragem mensal incorreta: %v", monthly)
+	}
+}
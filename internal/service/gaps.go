@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	GapDirectionUp   = "up"
+	GapDirectionDown = "down"
+)
+
+// GetTickerGaps lista os gaps de abertura do ticker em [startDate, endDate]
+// com módulo a partir de minPercent. Devolve ErrNotFound se o ticker não
+// negociou no período.
+func (s *AnalysisService) GetTickerGaps(ctx context.Context, ticker string, startDate, endDate time.Time, minPercent float64) (*domain.GapAnalysis, error) {
+	gaps, err := s.queryGaps(ctx, ticker, startDate, endDate, minPercent)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(gaps) == 0 {
+		var traded bool
+		err := s.pool.QueryRow(ctx, `
+            SELECT EXISTS (
+                SELECT 1 FROM daily_aggregations
+                WHERE codigo_instrumento = $1
+                AND data_negocio BETWEEN $2 AND $3
+            )
+        `, ticker, startDate, endDate).Scan(&traded)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar pregões do ticker: %w", err)
+		}
+		if !traded {
+			return nil, ErrNotFound
+		}
+	}
+
+	return &domain.GapAnalysis{
+		Ticker:     ticker,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		MinPercent: minPercent,
+		Summary:    summarizeGaps(gaps),
+		Gaps:       gaps,
+	}, nil
+}
+
+// GetGapRanking lista os maiores gaps do pregão entre todos os tickers, em
+// módulo; direction vazio considera os dois lados. O resumo cobre todos os
+// gaps do filtro, não só os limit primeiros.
+func (s *AnalysisService) GetGapRanking(ctx context.Context, date time.Time, minPercent float64, direction string, limit int) (*domain.GapRanking, error) {
+	all, err := s.queryGaps(ctx, "", date, date, minPercent)
+	if err != nil {
+		return nil, err
+	}
+
+	gaps := []domain.OpeningGap{}
+	for _, gap := range all {
+		if direction == "" || gap.Direction == direction {
+			gaps = append(gaps, gap)
+		}
+	}
+
+	sort.SliceStable(gaps, func(i, j int) bool {
+		return math.Abs(gaps[i].GapPercent) > math.Abs(gaps[j].GapPercent)
+	})
+
+	ranking := &domain.GapRanking{
+		Date:       date,
+		MinPercent: minPercent,
+		Summary:    summarizeGaps(gaps),
+		Data:       gaps,
+	}
+	if len(gaps) > limit {
+		ranking.Data = gaps[:limit]
+	}
+
+	return ranking, nil
+}
+
+// queryGaps compara a abertura de cada pregão com o fechamento do pregão
+// anterior do ticker (buscado até 15 dias antes do período) e procura nos
+// negócios do dia o primeiro que alcança esse fechamento. Com ticker vazio,
+// considera todos os tickers.
+func (s *AnalysisService) queryGaps(ctx context.Context, ticker string, startDate, endDate time.Time, minPercent float64) ([]domain.OpeningGap, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("gaps"))
+
+	args := []interface{}{startDate, endDate, minPercent}
+	tickerFilter := ""
+	if ticker != "" {
+		args = append(args, ticker)
+		tickerFilter = "AND codigo_instrumento = $4"
+	}
+
+	query := fmt.Sprintf(`
+        WITH sessions AS (
+            SELECT *
+            FROM (
+                SELECT
+                    codigo_instrumento,
+                    data_negocio,
+                    open_price,
+                    close_price,
+                    LAG(close_price) OVER w as prev_close,
+                    LAG(data_negocio) OVER w as prev_date
+                FROM daily_aggregations
+                WHERE data_negocio <= $2
+                AND data_negocio >= $1::date - INTERVAL '15 days'
+                %s
+                WINDOW w AS (PARTITION BY codigo_instrumento ORDER BY data_negocio)
+            ) d
+            WHERE data_negocio >= $1
+            AND prev_close > 0
+            AND open_price <> prev_close
+            AND ABS(open_price - prev_close) / prev_close * 100 >= $3
+        )
+        SELECT
+            g.codigo_instrumento,
+            g.data_negocio,
+            g.prev_date,
+            g.prev_close,
+            g.open_price,
+            g.close_price,
+            f.opened_at,
+            f.filled_at
+        FROM sessions g
+        LEFT JOIN LATERAL (
+            SELECT
+                EXTRACT(EPOCH FROM MIN(t.hora_fechamento))::bigint as opened_at,
+                EXTRACT(EPOCH FROM MIN(t.hora_fechamento) FILTER (
+                    WHERE CASE WHEN g.open_price > g.prev_close
+                        THEN t.preco_negocio <= g.prev_close
+                        ELSE t.preco_negocio >= g.prev_close
+                    END
+                ))::bigint as filled_at
+            FROM trades t
+            WHERE t.codigo_instrumento = g.codigo_instrumento
+            AND t.data_negocio = g.data_negocio
+        ) f ON true
+        ORDER BY g.data_negocio, g.codigo_instrumento
+    `, tickerFilter)
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("gaps", "error").Inc()
+		return nil, fmt.Errorf("erro ao calcular gaps: %w", err)
+	}
+	defer rows.Close()
+
+	gaps := []domain.OpeningGap{}
+	for rows.Next() {
+		var gap domain.OpeningGap
+		var openedAt, filledAt *int64
+		err := rows.Scan(
+			&gap.Ticker,
+			&gap.Date,
+			&gap.PrevDate,
+			&gap.PrevClose,
+			&gap.Open,
+			&gap.Close,
+			&openedAt,
+			&filledAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear gap: %w", err)
+		}
+
+		classifyGap(&gap, openedAt, filledAt)
+		gaps = append(gaps, gap)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar gaps: %w", err)
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("gaps", "success").Inc()
+	return gaps, nil
+}
+
+// classifyGap preenche percentual, direção e preenchimento a partir dos
+// horários em segundos desde a meia-noite.
+func classifyGap(gap *domain.OpeningGap, openedAt, filledAt *int64) {
+	gap.GapPercent, _ = gap.Open.Sub(gap.PrevClose).Div(gap.PrevClose).Mul(decimal.NewFromInt(100)).Float64()
+
+	gap.Direction = GapDirectionUp
+	if gap.GapPercent < 0 {
+		gap.Direction = GapDirectionDown
+	}
+
+	if openedAt != nil {
+		gap.OpenedAt = clockTime(*openedAt)
+	}
+	if filledAt != nil {
+		gap.Filled = true
+		gap.FilledAt = clockTime(*filledAt)
+		if openedAt != nil {
+			minutes := float64(*filledAt-*openedAt) / 60
+			gap.MinutesToFill = &minutes
+		}
+	}
+}
+
+func clockTime(seconds int64) string {
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+}
+
+func summarizeGaps(gaps []domain.OpeningGap) domain.GapSummary {
+	var all, up, down gapAccumulator
+	for _, gap := range gaps {
+		all.add(gap)
+		if gap.Direction == GapDirectionUp {
+			up.add(gap)
+		} else {
+			down.add(gap)
+		}
+	}
+
+	return domain.GapSummary{
+		GapStats: all.stats(),
+		Up:       up.stats(),
+		Down:     down.stats(),
+	}
+}
+
+type gapAccumulator struct {
+	gaps, filled, timed int
+	gapSum, minutesSum  float64
+}
+
+func (a *gapAccumulator) add(gap domain.OpeningGap) {
+	a.gaps++
+	a.gapSum += math.Abs(gap.GapPercent)
+	if gap.Filled {
+		a.filled++
+	}
+	if gap.MinutesToFill != nil {
+		a.timed++
+		a.minutesSum += *gap.MinutesToFill
+	}
+}
+
+func (a gapAccumulator) stats() domain.GapStats {
+	stats := domain.GapStats{Gaps: a.gaps, Filled: a.filled}
+	if a.gaps > 0 {
+		stats.FillRate = float64(a.filled) / float64(a.gaps) * 100
+		stats.AvgGapPercent = a.gapSum / float64(a.gaps)
+	}
+	if a.timed > 0 {
+		stats.AvgMinutesToFill = a.minutesSum / float64(a.timed)
+	}
+	return stats
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestClassifyGap(t *testing.T) {
+	opened, filled := int64(10*3600), int64(10*3600+45*60)
+
+	gap := domain.OpeningGap{PrevClose: decimal.NewFromInt(20), Open: decimal.NewFromInt(21)}
+	classifyGap(&gap, &opened, &filled)
+
+	if gap.Direction != GapDirectionUp || math.Abs(gap.GapPercent-5) > 1e-9 {
+		t.Errorf("esperado gap de alta de 5%%, recebido %s %v", gap.Direction, gap.GapPercent)
+	}
+	if !gap.Filled || gap.OpenedAt != "10:00:00" || gap.FilledAt != "10:45:00" || *gap.MinutesToFill != 45 {
+		t.Errorf("preenchimento incorreto: %+v", gap)
+	}
+
+	gap = domain.OpeningGap{PrevClose: decimal.NewFromInt(20), Open: decimal.NewFromInt(19)}
+	classifyGap(&gap, &opened, nil)
+	if gap.Direction != GapDirectionDown || gap.Filled || gap.MinutesToFill != nil {
+		t.Errorf("gap de baixa não preenchido incorreto: %+v", gap)
+	}
+}
+
+func TestSummarizeGaps(t *testing.T) {
+	minutes := func(v float64) *float64 { return &v }
+	gaps := []domain.OpeningGap{
+		{GapPercent: 2, Direction: GapDirectionUp, Filled: true, MinutesToFill: minutes(30)},
+		{GapPercent: 4, Direction: GapDirectionUp},
+		{GapPercent: -3, Direction: GapDirectionDown, Filled: true, MinutesToFill: minutes(90)},
+	}
+
+	summary := summarizeGaps(gaps)
+	if summary.Gaps != 3 || summary.Filled != 2 || math.Abs(summary.FillRate-200.0/3) > 1e-9 {
+		t.Errorf("resumo geral incorreto: %+v", summary.GapStats)
+	}
+	if summary.AvgGapPercent != 3 || summary.AvgMinutesToFill != 60 {
+		t.Errorf("médias incorretas: %+v", summary.GapStats)
+	}
+	if summary.Up.Gaps != 2 || summary.Up.FillRate != 50 || summary.Down.AvgMinutesToFill != 90 {
+		t.Errorf("resumo por direção incorreto: %+v %+v", summary.Up, summary.Down)
+	}
+}
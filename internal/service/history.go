@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+const (
+	GranularityDay     = "day"
+	GranularityWeek    = "week"
+	GranularityMonth   = "month"
+	GranularityQuarter = "quarter"
+	GranularityYear    = "year"
+)
+
+func ValidGranularity(granularity string) bool {
+	switch granularity {
+	case GranularityDay, GranularityWeek, GranularityMonth, GranularityQuarter, GranularityYear:
+		return true
+	}
+	return false
+}
+
+// GetTickerHistoryByPeriod agrega o histórico diário por semana (ISO, de
+// segunda a domingo), mês, trimestre ou ano civil. Como daily_aggregations
+// já é uma consolidação por pregão, o rollup percorre no máximo ~252 linhas
+// por ano do ticker. Períodos cortados pelo intervalo pedido ou ainda em
+// andamento no último pregão carregado vêm com Partial.
+func (s *TradeService) GetTickerHistoryByPeriod(ctx context.Context, ticker, granularity string, startDate, endDate *time.Time) ([]domain.DailyAggregation, error) {
+	if granularity == GranularityDay {
+		return s.GetTickerHistory(ctx, ticker, startDate, endDate)
+	}
+	if !ValidGranularity(granularity) {
+		return nil, fmt.Errorf("granularidade inválida: %s", granularity)
+	}
+
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("ticker_history_period"))
+
+	query := `
+        SELECT
+            MIN(data_negocio) as first_session,
+            MAX(data_negocio) as last_session,
+            COUNT(*) as trading_days,
+            MAX(max_price) as max_price,
+            MIN(min_price) as min_price,
+            AVG(avg_price) as avg_price,
+            SUM(total_volume) as total_volume,
+            SUM(trade_count) as trade_count,
+            (ARRAY_AGG(open_price ORDER BY data_negocio))[1] as open_price,
+            (ARRAY_AGG(close_price ORDER BY data_negocio DESC))[1] as close_price,
+            SUM(financial_volume) as financial_volume,
+            ` + periodVWAP(ticker) + ` as vwap,
+            SUM(block_volume) as block_volume
+        FROM ` + dailySource(ticker) + `
+        WHERE codigo_instrumento = $1
+          AND ($2::date IS NULL OR data_negocio >= $2)
+          AND ($3::date IS NULL OR data_negocio <= $3)
+        GROUP BY date_trunc($4::text, data_negocio)
+        ORDER BY first_session DESC
+    `
+
+	rows, err := s.pool.Query(ctx, query, ticker, startDate, endDate, granularity)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("ticker_history_period", "error").Inc()
+		return nil, fmt.Errorf("erro ao buscar histórico: %w", err)
+	}
+	defer rows.Close()
+
+	var history []domain.DailyAggregation
+	for rows.Next() {
+		agg := domain.DailyAggregation{CodigoInstrumento: ticker}
+		var periodEnd time.Time
+		var vwap *decimal.Decimal
+
+		err := rows.Scan(
+			&agg.DataNegocio,
+			&periodEnd,
+			&agg.TradingDays,
+			&agg.MaxPrice,
+			&agg.MinPrice,
+			&agg.AvgPrice,
+			&agg.TotalVolume,
+			&agg.TradeCount,
+			&agg.OpenPrice,
+			&agg.ClosePrice,
+			&agg.FinancialVolume,
+			&vwap,
+			&agg.BlockVolume,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear linha: %w", err)
+		}
+
+		agg.PeriodEnd = &periodEnd
+		if vwap != nil {
+			agg.VWAP = *vwap
+		}
+		if agg.TotalVolume > 0 {
+			agg.BlockVolumePercent = float64(agg.BlockVolume) / float64(agg.TotalVolume) * 100
+		}
+
+		history = append(history, agg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar resultados: %w", err)
+	}
+
+	if len(history) > 0 {
+		limit := endDate
+		if latest, err := s.LatestSession(ctx); err == nil && (limit == nil || latest.Before(*limit)) {
+			limit = &latest
+		}
+		markPartialPeriods(history, granularity, startDate, limit)
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("ticker_history_period", "success").Inc()
+	logger.Info("histórico por período recuperado",
+		zap.String("ticker", ticker),
+		zap.String("granularity", granularity),
+		zap.Int("records", len(history)))
+
+	return history, nil
+}
+
+// markPartialPeriods marca os períodos que começam antes de startDate ou
+// terminam depois de limit, comparando com o primeiro e o último dia útil
+// do período civil.
+func markPartialPeriods(history []domain.DailyAggregation, granularity string, startDate, limit *time.Time) {
+	for i := range history {
+		first, last := periodBounds(history[i].DataNegocio, granularity)
+		if startDate != nil && startDate.After(first) {
+			history[i].Partial = true
+		}
+		if limit != nil && limit.Before(last) {
+			history[i].Partial = true
+		}
+	}
+}
+
+// periodBounds devolve o primeiro e o último dia útil (segunda a sexta) do
+// período civil que contém date.
+func periodBounds(date time.Time, granularity string) (time.Time, time.Time) {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+
+	var start, end time.Time
+	switch granularity {
+	case GranularityWeek:
+		start = date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+		end = start.AddDate(0, 0, 6)
+	case GranularityMonth:
+		start = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+		end = start.AddDate(0, 1, -1)
+	case GranularityQuarter:
+		start = time.Date(date.Year(), date.Month()-(date.Month()-1)%3, 1, 0, 0, 0, 0, date.Location())
+		end = start.AddDate(0, 3, -1)
+	case GranularityYear:
+		start = time.Date(date.Year(), 1, 1, 0, 0, 0, 0, date.Location())
+		end = start.AddDate(1, 0, -1)
+	default:
+		return date, date
+	}
+
+	for start.Weekday() == time.Saturday || start.Weekday() == time.Sunday {
+		start = start.AddDate(0, 0, 1)
+	}
+	for end.Weekday() == time.Saturday || end.Weekday() == time.Sunday {
+		end = end.AddDate(0, 0, -1)
+	}
+	return start, end
+}
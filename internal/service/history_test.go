@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+)
+
+func TestPeriodBounds(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		granularity string
+		date        time.Time
+		first, last time.Time
+	}{
+		{GranularityWeek, day(2024, 3, 13), day(2024, 3, 11), day(2024, 3, 15)},
+		{GranularityWeek, day(2024, 3, 17), day(2024, 3, 11), day(2024, 3, 15)},
+		{GranularityMonth, day(2024, 6, 10), day(2024, 6, 3), day(2024, 6, 28)},
+		{GranularityQuarter, day(2024, 8, 20), day(2024, 7, 1), day(2024, 9, 30)},
+		{GranularityYear, day(2022, 5, 2), day(2022, 1, 3), day(2022, 12, 30)},
+	}
+
+	for _, tt := range tests {
+		first, last := periodBounds(tt.date, tt.granularity)
+		if !first.Equal(tt.first) || !last.Equal(tt.last) {
+			t.Errorf("%s %s: esperado %s a %s, recebido %s a %s", tt.granularity, tt.date.Format("2006-01-02"),
+				tt.first.Format("2006-01-02"), tt.last.Format("2006-01-02"), first.Format("2006-01-02"), last.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestMarkPartialPeriods(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2024, m, d, 0, 0, 0, 0, time.UTC) }
+	history := []domain.DailyAggregation{
+		{DataNegocio: day(4, 1)},
+		{DataNegocio: day(3, 1)},
+		{DataNegocio: day(2, 15)},
+	}
+
+	start, limit := day(2, 15), day(4, 10)
+	markPartialPeriods(history, GranularityMonth, &start, &limit)
+
+	if !history[0].Partial || history[1].Partial || !history[2].Partial {
+		t.Errorf("esperado só março completo: %v %v %v", history[0].Partial, history[1].Partial, history[2].Partial)
+	}
+}
@@ -0,0 +1,759 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// IndexTickerPrefix identifica um índice customizado nos endpoints de
+// histórico e estatísticas (ex.: IDX:BANCOS).
+const IndexTickerPrefix = "IDX:"
+
+const (
+	IndexWeightFixed  = "fixed"
+	IndexWeightEqual  = "equal"
+	IndexWeightVolume = "volume"
+
+	IndexRebalanceNone      = "none"
+	IndexRebalanceMonthly   = "monthly"
+	IndexRebalanceQuarterly = "quarterly"
+
+	// IndexVolumeWindow é a janela, em pregões, do volume financeiro médio
+	// usado na ponderação por volume.
+	IndexVolumeWindow = 20
+
+	// IndexNameMaxLength acompanha a coluna custom_indexes.name.
+	IndexNameMaxLength = 100
+)
+
+var DefaultIndexBaseValue = decimal.NewFromInt(1000)
+
+var ErrIndexExists = errors.New("índice já existe")
+
+var indexCodePattern = regexp.MustCompile(`^[A-Z0-9_]{1,16}$`)
+
+// indexSelect traz a definição com o último nível calculado.
+const indexSelect = `
+        SELECT i.id, i.code, i.name, i.weighting, i.rebalance, i.base_date, i.base_value, i.created_at,
+               l.data_negocio, l.open_level, l.close_level, l.total_volume, l.financial_volume,
+               l.trade_count, l.rebalanced
+        FROM custom_indexes i
+        LEFT JOIN LATERAL (
+            SELECT *
+            FROM custom_index_levels
+            WHERE index_id = i.id
+            ORDER BY data_negocio DESC
+            LIMIT 1
+        ) l ON true
+    `
+
+type IndexService struct {
+	pool *pgxpool.Pool
+}
+
+func NewIndexService(pool *pgxpool.Pool) *IndexService {
+	return &IndexService{pool: pool}
+}
+
+// IsIndexTicker indica se o ticker se refere a um índice customizado.
+func IsIndexTicker(ticker string) bool {
+	return strings.HasPrefix(ticker, IndexTickerPrefix)
+}
+
+// dailySource escolhe a fonte das agregações diárias: os níveis dos índices
+// customizados têm o mesmo formato de daily_aggregations.
+func dailySource(ticker string) string {
+	if IsIndexTicker(ticker) {
+		return "index_daily_aggregations"
+	}
+	return "daily_aggregations"
+}
+
+// periodVWAP é o VWAP de um período de daily_aggregations. Nos índices o
+// quociente daria o preço médio das ações, fora da escala dos níveis, por
+// isso fica nulo como a coluna vwap de index_daily_aggregations.
+func periodVWAP(ticker string) string {
+	if IsIndexTicker(ticker) {
+		return "NULL::numeric"
+	}
+	return "SUM(financial_volume) / NULLIF(SUM(total_volume), 0)"
+}
+
+// NormalizeIndex aplica os padrões: nome igual ao code, base 1000, sem
+// rebalanceamento e pesos descartados fora da ponderação fixed.
+func NormalizeIndex(index *domain.CustomIndex) {
+	if index.Name == "" {
+		index.Name = index.Code
+	}
+	if index.Rebalance == "" {
+		index.Rebalance = IndexRebalanceNone
+	}
+	if index.BaseValue.IsZero() {
+		index.BaseValue = DefaultIndexBaseValue
+	}
+	if index.Weighting != IndexWeightFixed {
+		for i := range index.Constituents {
+			index.Constituents[i].Weight = nil
+		}
+	}
+}
+
+func ValidateIndex(index domain.CustomIndex) error {
+	if !indexCodePattern.MatchString(index.Code) {
+		return fmt.Errorf("code deve ter até 16 letras maiúsculas, dígitos ou _")
+	}
+	if utf8.RuneCountInString(index.Name) > IndexNameMaxLength {
+		return fmt.Errorf("name deve ter até %d caracteres", IndexNameMaxLength)
+	}
+	switch index.Weighting {
+	case IndexWeightFixed, IndexWeightEqual, IndexWeightVolume:
+	default:
+		return fmt.Errorf("weighting inválido: use fixed, equal ou volume")
+	}
+	switch index.Rebalance {
+	case IndexRebalanceNone, IndexRebalanceMonthly, IndexRebalanceQuarterly:
+	default:
+		return fmt.Errorf("rebalance inválido: use none, monthly ou quarterly")
+	}
+	if index.BaseDate.IsZero() {
+		return fmt.Errorf("base_date é obrigatório")
+	}
+	if !index.BaseValue.IsPositive() {
+		return fmt.Errorf("base_value deve ser positivo")
+	}
+	if len(index.Constituents) == 0 {
+		return fmt.Errorf("informe ao menos um constituinte")
+	}
+
+	seen := make(map[string]bool, len(index.Constituents))
+	for _, constituent := range index.Constituents {
+		if constituent.Ticker == "" {
+			return fmt.Errorf("ticker do constituinte é obrigatório")
+		}
+		if utf8.RuneCountInString(constituent.Ticker) > TickerMaxLength {
+			return fmt.Errorf("constituinte %s: ticker deve ter até %d caracteres", constituent.Ticker, TickerMaxLength)
+		}
+		if IsIndexTicker(constituent.Ticker) {
+			return fmt.Errorf("constituinte %s não pode ser um índice", constituent.Ticker)
+		}
+		if seen[constituent.Ticker] {
+			return fmt.Errorf("constituinte %s repetido", constituent.Ticker)
+		}
+		seen[constituent.Ticker] = true
+
+		if index.Weighting == IndexWeightFixed && (constituent.Weight == nil || !constituent.Weight.IsPositive()) {
+			return fmt.Errorf("constituinte %s: weight positivo é obrigatório na ponderação fixed", constituent.Ticker)
+		}
+	}
+	return nil
+}
+
+// CreateIndex grava a definição e calcula os níveis desde a data base.
+func (s *IndexService) CreateIndex(ctx context.Context, index domain.CustomIndex) (*domain.CustomIndex, error) {
+	NormalizeIndex(&index)
+	if err := ValidateIndex(index); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM custom_indexes WHERE code = $1)", index.Code).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("erro ao buscar índice: %w", err)
+	}
+	if exists {
+		return nil, ErrIndexExists
+	}
+
+	err = tx.QueryRow(ctx, `
+        INSERT INTO custom_indexes (code, name, weighting, rebalance, base_date, base_value)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, created_at
+    `, index.Code, index.Name, index.Weighting, index.Rebalance, index.BaseDate, index.BaseValue).Scan(&index.ID, &index.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar índice: %w", err)
+	}
+
+	for _, constituent := range index.Constituents {
+		_, err := tx.Exec(ctx, `
+            INSERT INTO custom_index_constituents (index_id, codigo_instrumento, weight)
+            VALUES ($1, $2, $3)
+        `, index.ID, constituent.Ticker, constituent.Weight)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao criar constituinte %s: %w", constituent.Ticker, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("erro no commit: %w", err)
+	}
+
+	index.Ticker = IndexTickerPrefix + index.Code
+	if _, err := s.updateIndex(ctx, &index); err != nil {
+		return nil, err
+	}
+
+	return s.GetIndex(ctx, index.Code)
+}
+
+func (s *IndexService) ListIndexes(ctx context.Context) ([]domain.CustomIndex, error) {
+	rows, err := s.pool.Query(ctx, indexSelect+" ORDER BY i.code")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar índices: %w", err)
+	}
+	defer rows.Close()
+
+	indexes := []domain.CustomIndex{}
+	for rows.Next() {
+		index, err := scanIndex(rows)
+		if err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, *index)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar índices: %w", err)
+	}
+
+	return indexes, nil
+}
+
+func (s *IndexService) GetIndex(ctx context.Context, code string) (*domain.CustomIndex, error) {
+	row := s.pool.QueryRow(ctx, indexSelect+" WHERE i.code = $1", code)
+
+	index, err := scanIndex(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index.Constituents, err = s.constituents(ctx, index.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+func (s *IndexService) DeleteIndex(ctx context.Context, code string) error {
+	tag, err := s.pool.Exec(ctx, "DELETE FROM custom_indexes WHERE code = $1", code)
+	if err != nil {
+		return fmt.Errorf("erro ao remover índice: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *IndexService) GetLevels(ctx context.Context, code string, startDate, endDate *time.Time) ([]domain.IndexLevel, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("index_levels"))
+
+	var id int64
+	err := s.pool.QueryRow(ctx, "SELECT id FROM custom_indexes WHERE code = $1", code).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar índice: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+        SELECT data_negocio, open_level, close_level, total_volume, financial_volume, trade_count, rebalanced
+        FROM custom_index_levels
+        WHERE index_id = $1
+          AND ($2::date IS NULL OR data_negocio >= $2)
+          AND ($3::date IS NULL OR data_negocio <= $3)
+        ORDER BY data_negocio
+    `, id, startDate, endDate)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("index_levels", "error").Inc()
+		return nil, fmt.Errorf("erro ao buscar níveis do índice: %w", err)
+	}
+	defer rows.Close()
+
+	levels := []domain.IndexLevel{}
+	for rows.Next() {
+		var level domain.IndexLevel
+		err := rows.Scan(
+			&level.Date,
+			&level.Open,
+			&level.Close,
+			&level.TotalVolume,
+			&level.FinancialVolume,
+			&level.TradeCount,
+			&level.Rebalanced,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear nível do índice: %w", err)
+		}
+		levels = append(levels, level)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar níveis do índice: %w", err)
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("index_levels", "success").Inc()
+	return levels, nil
+}
+
+// UpdateIndexes recalcula, após cargas e reconstruções, os índices cujos
+// pregões mudaram desde o último cálculo, inclusive por cargas retroativas.
+// Cada índice é refeito a partir do último rebalanceamento anterior ao
+// primeiro pregão alterado, sem refazer a série inteira. Devolve quantos
+// índices foram recalculados.
+func (s *IndexService) UpdateIndexes(ctx context.Context) (int, error) {
+	indexes, err := s.ListIndexes(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for i := range indexes {
+		index := &indexes[i]
+		index.Constituents, err = s.constituents(ctx, index.ID)
+		if err != nil {
+			return updated, err
+		}
+		changed, err := s.updateIndex(ctx, index)
+		if err != nil {
+			return updated, fmt.Errorf("índice %s: %w", index.Code, err)
+		}
+		if changed {
+			updated++
+		}
+	}
+
+	return updated, nil
+}
+
+const indexLevelInsert = `
+    INSERT INTO custom_index_levels
+        (index_id, data_negocio, open_level, close_level, total_volume, financial_volume, trade_count, rebalanced)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    ON CONFLICT (index_id, data_negocio) DO UPDATE SET
+        open_level = EXCLUDED.open_level,
+        close_level = EXCLUDED.close_level,
+        total_volume = EXCLUDED.total_volume,
+        financial_volume = EXCLUDED.financial_volume,
+        trade_count = EXCLUDED.trade_count,
+        rebalanced = EXCLUDED.rebalanced
+`
+
+// updateIndex recalcula os níveis a partir do primeiro pregão desatualizado;
+// devolve false se o índice já estava em dia.
+func (s *IndexService) updateIndex(ctx context.Context, index *domain.CustomIndex) (bool, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("index_update"))
+
+	tickers := make([]string, len(index.Constituents))
+	for i, constituent := range index.Constituents {
+		tickers[i] = constituent.Ticker
+	}
+
+	since, err := s.indexStaleSince(ctx, index.ID, tickers, index.BaseDate)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("index_update", "error").Inc()
+		return false, err
+	}
+	if since == nil {
+		return false, nil
+	}
+
+	from := index.BaseDate
+	fromLevel := index.BaseValue
+	incremental := false
+
+	// O rebalanceamento de partida precisa ser anterior a since: os pesos e
+	// o nível dele dependem só dos pregões até a própria data.
+	err = s.pool.QueryRow(ctx, `
+        SELECT data_negocio, close_level
+        FROM custom_index_levels
+        WHERE index_id = $1 AND rebalanced AND data_negocio < $2
+        ORDER BY data_negocio DESC
+        LIMIT 1
+    `, index.ID, *since).Scan(&from, &fromLevel)
+	if err == nil {
+		incremental = true
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return false, fmt.Errorf("erro ao buscar último rebalanceamento: %w", err)
+	}
+
+	sessions, err := s.indexSessions(ctx, tickers, from)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("index_update", "error").Inc()
+		return false, err
+	}
+
+	levels := computeIndexLevels(*index, sessions, from, fromLevel)
+	if incremental && len(levels) > 0 {
+		// O pregão de partida já está gravado com o nível usado como base.
+		levels = levels[1:]
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	cleanup := "DELETE FROM custom_index_levels WHERE index_id = $1 AND data_negocio > $2"
+	if !incremental {
+		cleanup = "DELETE FROM custom_index_levels WHERE index_id = $1 AND data_negocio >= $2"
+	}
+	if _, err := tx.Exec(ctx, cleanup, index.ID, from); err != nil {
+		return false, fmt.Errorf("erro ao limpar níveis do índice: %w", err)
+	}
+
+	batch := &pgx.Batch{}
+	for _, level := range levels {
+		batch.Queue(indexLevelInsert,
+			index.ID, level.Date, level.Open, level.Close, level.TotalVolume, level.FinancialVolume, level.TradeCount, level.Rebalanced)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return false, fmt.Errorf("erro ao gravar níveis do índice: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("erro no commit: %w", err)
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("index_update", "success").Inc()
+	logger.Info("índice atualizado",
+		zap.String("index", index.Code),
+		zap.Bool("incremental", incremental),
+		zap.Time("since", *since),
+		zap.Int("levels", len(levels)))
+
+	return true, nil
+}
+
+// indexStaleSince compara os níveis gravados com a soma dos constituintes em
+// daily_aggregations e devolve o primeiro pregão divergente: novo, removido
+// ou com negócios e volumes diferentes dos usados no cálculo. Devolve nil se
+// os níveis estão em dia.
+func (s *IndexService) indexStaleSince(ctx context.Context, indexID int64, tickers []string, baseDate time.Time) (*time.Time, error) {
+	var since *time.Time
+	err := s.pool.QueryRow(ctx, `
+        WITH sessions AS (
+            SELECT data_negocio,
+                   SUM(total_volume) as total_volume,
+                   ROUND(SUM(financial_volume), 2) as financial_volume,
+                   SUM(trade_count) as trade_count
+            FROM daily_aggregations
+            WHERE codigo_instrumento = ANY($2)
+              AND data_negocio >= $3
+            GROUP BY data_negocio
+        ),
+        levels AS (
+            SELECT data_negocio, total_volume, financial_volume, trade_count
+            FROM custom_index_levels
+            WHERE index_id = $1
+        )
+        SELECT MIN(COALESCE(d.data_negocio, l.data_negocio))
+        FROM sessions d
+        FULL JOIN levels l ON l.data_negocio = d.data_negocio
+        WHERE d.data_negocio IS NULL
+           OR l.data_negocio IS NULL
+           OR d.total_volume <> l.total_volume
+           OR d.financial_volume <> l.financial_volume
+           OR d.trade_count <> l.trade_count
+    `, indexID, tickers, baseDate).Scan(&since)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao comparar níveis do índice: %w", err)
+	}
+
+	return since, nil
+}
+
+// indexSessions carrega as barras dos constituintes a partir de from,
+// incluindo os IndexVolumeWindow pregões anteriores para a ponderação por
+// volume e para o último fechamento de quem não negociou.
+func (s *IndexService) indexSessions(ctx context.Context, tickers []string, from time.Time) ([]indexSession, error) {
+	rows, err := s.pool.Query(ctx, `
+        WITH lookback AS (
+            SELECT MIN(data_negocio) as start_date
+            FROM (
+                SELECT DISTINCT data_negocio
+                FROM daily_aggregations
+                WHERE data_negocio < $2
+                ORDER BY data_negocio DESC
+                LIMIT $3
+            ) d
+        )
+        SELECT data_negocio, codigo_instrumento, open_price, close_price,
+               total_volume, financial_volume, trade_count
+        FROM daily_aggregations
+        WHERE codigo_instrumento = ANY($1)
+          AND data_negocio >= COALESCE((SELECT start_date FROM lookback), $2)
+        ORDER BY data_negocio, codigo_instrumento
+    `, tickers, from, IndexVolumeWindow)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pregões dos constituintes: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []indexSession
+	for rows.Next() {
+		var date time.Time
+		var ticker string
+		var bar indexBar
+		if err := rows.Scan(&date, &ticker, &bar.open, &bar.close, &bar.volume, &bar.financial, &bar.trades); err != nil {
+			return nil, fmt.Errorf("erro ao escanear pregão: %w", err)
+		}
+
+		if len(sessions) == 0 || !sessions[len(sessions)-1].date.Equal(date) {
+			sessions = append(sessions, indexSession{date: date, bars: make(map[string]indexBar)})
+		}
+		sessions[len(sessions)-1].bars[ticker] = bar
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar pregões: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (s *IndexService) constituents(ctx context.Context, indexID int64) ([]domain.IndexConstituent, error) {
+	rows, err := s.pool.Query(ctx, `
+        SELECT codigo_instrumento, weight
+        FROM custom_index_constituents
+        WHERE index_id = $1
+        ORDER BY codigo_instrumento
+    `, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar constituintes: %w", err)
+	}
+	defer rows.Close()
+
+	constituents := []domain.IndexConstituent{}
+	for rows.Next() {
+		var constituent domain.IndexConstituent
+		if err := rows.Scan(&constituent.Ticker, &constituent.Weight); err != nil {
+			return nil, fmt.Errorf("erro ao escanear constituinte: %w", err)
+		}
+		constituents = append(constituents, constituent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar constituintes: %w", err)
+	}
+
+	return constituents, nil
+}
+
+func scanIndex(row pgx.Row) (*domain.CustomIndex, error) {
+	var index domain.CustomIndex
+	var date *time.Time
+	var openLevel, closeLevel, financial *decimal.Decimal
+	var volume, trades *int64
+	var rebalanced *bool
+
+	err := row.Scan(
+		&index.ID,
+		&index.Code,
+		&index.Name,
+		&index.Weighting,
+		&index.Rebalance,
+		&index.BaseDate,
+		&index.BaseValue,
+		&index.CreatedAt,
+		&date,
+		&openLevel,
+		&closeLevel,
+		&volume,
+		&financial,
+		&trades,
+		&rebalanced,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao escanear índice: %w", err)
+	}
+
+	index.Ticker = IndexTickerPrefix + index.Code
+	if date != nil {
+		index.LastLevel = &domain.IndexLevel{
+			Date:            *date,
+			Open:            *openLevel,
+			Close:           *closeLevel,
+			TotalVolume:     *volume,
+			FinancialVolume: *financial,
+			TradeCount:      int(*trades),
+			Rebalanced:      *rebalanced,
+		}
+	}
+
+	return &index, nil
+}
+
+type indexBar struct {
+	open      decimal.Decimal
+	close     decimal.Decimal
+	volume    int64
+	financial decimal.Decimal
+	trades    int
+}
+
+type indexSession struct {
+	date time.Time
+	bars map[string]indexBar
+}
+
+// computeIndexLevels calcula os níveis a partir do primeiro pregão em ou após
+// from, que recebe fromLevel. Em cada rebalanceamento (o pregão de partida e
+// o primeiro pregão de cada mês ou trimestre) as quantidades teóricas são
+// refeitas no fechamento para que cada constituinte pese w_i no nível; entre
+// rebalanceamentos o índice segue os preços. Quem não negociou no pregão
+// entra pelo último fechamento conhecido. Os pregões anteriores a from só
+// alimentam esses fechamentos e a ponderação por volume.
+func computeIndexLevels(index domain.CustomIndex, sessions []indexSession, from time.Time, fromLevel decimal.Decimal) []domain.IndexLevel {
+	lastClose := make(map[string]decimal.Decimal, len(index.Constituents))
+	units := make(map[string]decimal.Decimal, len(index.Constituents))
+	var levels []domain.IndexLevel
+
+	for i, session := range sessions {
+		var openLevel, closeLevel, financial decimal.Decimal
+		var volume int64
+		var trades int
+
+		started := len(levels) > 0
+		for _, constituent := range index.Constituents {
+			ticker := constituent.Ticker
+			prev, known := lastClose[ticker]
+			bar, traded := session.bars[ticker]
+
+			if started {
+				if traded {
+					openLevel = openLevel.Add(units[ticker].Mul(bar.open))
+					closeLevel = closeLevel.Add(units[ticker].Mul(bar.close))
+				} else if known {
+					openLevel = openLevel.Add(units[ticker].Mul(prev))
+					closeLevel = closeLevel.Add(units[ticker].Mul(prev))
+				}
+			}
+			if traded {
+				lastClose[ticker] = bar.close
+				volume += bar.volume
+				financial = financial.Add(bar.financial)
+				trades += bar.trades
+			}
+		}
+
+		if session.date.Before(from) {
+			continue
+		}
+
+		rebalance := false
+		if !started {
+			if len(lastClose) == 0 {
+				continue
+			}
+			openLevel, closeLevel = fromLevel, fromLevel
+			rebalance = true
+		} else {
+			rebalance = indexRebalanceDue(index.Rebalance, levels[len(levels)-1].Date, session.date)
+		}
+
+		if rebalance {
+			weights := indexWeights(index, sessions[:i+1], lastClose)
+			units = make(map[string]decimal.Decimal, len(weights))
+			for ticker, weight := range weights {
+				units[ticker] = weight.Mul(closeLevel).DivRound(lastClose[ticker], 16)
+			}
+		}
+
+		levels = append(levels, domain.IndexLevel{
+			Date:            session.date,
+			Open:            openLevel.Round(6),
+			Close:           closeLevel.Round(6),
+			TotalVolume:     volume,
+			FinancialVolume: financial,
+			TradeCount:      trades,
+			Rebalanced:      rebalance,
+		})
+	}
+
+	return levels
+}
+
+func indexRebalanceDue(rebalance string, previous, current time.Time) bool {
+	switch rebalance {
+	case IndexRebalanceMonthly:
+		return previous.Year() != current.Year() || previous.Month() != current.Month()
+	case IndexRebalanceQuarterly:
+		return previous.Year() != current.Year() || (previous.Month()-1)/3 != (current.Month()-1)/3
+	}
+	return false
+}
+
+// indexWeights devolve pesos que somam 1 entre os constituintes com preço
+// conhecido. Na ponderação por volume usa o volume financeiro médio dos
+// últimos IndexVolumeWindow pregões; sem volume no período, cai para pesos
+// iguais.
+func indexWeights(index domain.CustomIndex, sessions []indexSession, lastClose map[string]decimal.Decimal) map[string]decimal.Decimal {
+	raw := make(map[string]decimal.Decimal, len(index.Constituents))
+	for _, constituent := range index.Constituents {
+		if _, ok := lastClose[constituent.Ticker]; !ok {
+			continue
+		}
+		switch index.Weighting {
+		case IndexWeightFixed:
+			if constituent.Weight != nil {
+				raw[constituent.Ticker] = *constituent.Weight
+			}
+		case IndexWeightEqual:
+			raw[constituent.Ticker] = decimal.NewFromInt(1)
+		case IndexWeightVolume:
+			if len(sessions) > IndexVolumeWindow {
+				sessions = sessions[len(sessions)-IndexVolumeWindow:]
+			}
+			total := decimal.Zero
+			for _, session := range sessions {
+				total = total.Add(session.bars[constituent.Ticker].financial)
+			}
+			raw[constituent.Ticker] = total
+		}
+	}
+
+	sum := decimal.Zero
+	for _, weight := range raw {
+		sum = sum.Add(weight)
+	}
+	if !sum.IsPositive() {
+		for ticker := range raw {
+			raw[ticker] = decimal.NewFromInt(1)
+		}
+		sum = decimal.NewFromInt(int64(len(raw)))
+	}
+
+	for ticker, weight := range raw {
+		raw[ticker] = weight.DivRound(sum, 16)
+	}
+	return raw
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestComputeIndexLevels(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2024, m, d, 0, 0, 0, 0, time.UTC) }
+	bar := func(open, close float64, financial int64) indexBar {
+		return indexBar{
+			open:      decimal.NewFromFloat(open),
+			close:     decimal.NewFromFloat(close),
+			volume:    100,
+			financial: decimal.NewFromInt(financial),
+			trades:    1,
+		}
+	}
+
+	sessions := []indexSession{
+		{date: day(1, 30), bars: map[string]indexBar{"AAAA3": bar(10, 10, 100), "BBBB4": bar(20, 20, 300)}},
+		{date: day(1, 31), bars: map[string]indexBar{"AAAA3": bar(10, 11, 100), "BBBB4": bar(20, 20, 300)}},
+		// BBBB4 não negocia: entra pelo último fechamento.
+		{date: day(2, 1), bars: map[string]indexBar{"AAAA3": bar(11, 12, 100)}},
+		{date: day(2, 2), bars: map[string]indexBar{"AAAA3": bar(12, 12, 100), "BBBB4": bar(20, 22, 300)}},
+	}
+
+	index := domain.CustomIndex{
+		Weighting:    IndexWeightEqual,
+		Rebalance:    IndexRebalanceMonthly,
+		Constituents: []domain.IndexConstituent{{Ticker: "AAAA3"}, {Ticker: "BBBB4"}},
+	}
+
+	levels := computeIndexLevels(index, sessions, day(1, 30), decimal.NewFromInt(1000))
+	if len(levels) != 4 {
+		t.Fatalf("esperado 4 níveis, recebido %d", len(levels))
+	}
+
+	// Base: 50 AAAA3 e 25 BBBB4. Em 01/02 rebalanceia no fechamento (1100):
+	// 45,83 AAAA3 e 27,5 BBBB4.
+	want := []struct {
+		open, close string
+		rebalanced  bool
+	}{
+		{"1000", "1000", true},
+		{"1000", "1050", false},
+		{"1050", "1100", true},
+		{"1100", "1155", false},
+	}
+	for i, w := range want {
+		if !levels[i].Open.Equal(decimal.RequireFromString(w.open)) || !levels[i].Close.Equal(decimal.RequireFromString(w.close)) {
+			t.Errorf("nível %d: esperado %s/%s, recebido %s/%s", i, w.open, w.close, levels[i].Open, levels[i].Close)
+		}
+		if levels[i].Rebalanced != w.rebalanced {
+			t.Errorf("nível %d: rebalanced esperado %v", i, w.rebalanced)
+		}
+	}
+	if levels[2].TotalVolume != 100 || levels[3].TradeCount != 2 {
+		t.Errorf("volume/negócios não somam só quem negociou: %+v", levels[2:])
+	}
+
+	// Recalcular a partir do último rebalanceamento reproduz a série.
+	incremental := computeIndexLevels(index, sessions, day(2, 1), levels[2].Close)
+	if len(incremental) != 2 || !incremental[1].Close.Equal(levels[3].Close) {
+		t.Errorf("recálculo incremental divergente: %+v", incremental)
+	}
+}
+
+func TestIndexWeightsVolume(t *testing.T) {
+	sessions := []indexSession{
+		{bars: map[string]indexBar{"AAAA3": {financial: decimal.NewFromInt(100)}, "BBBB4": {financial: decimal.NewFromInt(300)}}},
+	}
+	lastClose := map[string]decimal.Decimal{"AAAA3": decimal.NewFromInt(1), "BBBB4": decimal.NewFromInt(1)}
+	index := domain.CustomIndex{
+		Weighting:    IndexWeightVolume,
+		Constituents: []domain.IndexConstituent{{Ticker: "AAAA3"}, {Ticker: "BBBB4"}, {Ticker: "CCCC3"}},
+	}
+
+	weights := indexWeights(index, sessions, lastClose)
+	if len(weights) != 2 {
+		t.Fatalf("constituinte sem preço não deveria receber peso: %v", weights)
+	}
+	if !weights["AAAA3"].Equal(decimal.RequireFromString("0.25")) || !weights["BBBB4"].Equal(decimal.RequireFromString("0.75")) {
+		t.Errorf("pesos por volume incorretos: %v", weights)
+	}
+
+	sessions[0].bars = map[string]indexBar{}
+	weights = indexWeights(index, sessions, lastClose)
+	if !weights["AAAA3"].Equal(decimal.RequireFromString("0.5")) {
+		t.Errorf("sem volume deveria cair para pesos iguais: %v", weights)
+	}
+}
+
+func TestValidateIndexLengths(t *testing.T) {
+	index := func(name, ticker string) domain.CustomIndex {
+		index := domain.CustomIndex{
+			Code:         "BANCOS",
+			Name:         name,
+			Weighting:    IndexWeightEqual,
+			BaseDate:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Constituents: []domain.IndexConstituent{{Ticker: ticker}},
+		}
+		NormalizeIndex(&index)
+		return index
+	}
+
+	if err := ValidateIndex(index(strings.Repeat("ç", IndexNameMaxLength), "ITUB4")); err != nil {
+		t.Errorf("erro inesperado no limite: %v", err)
+	}
+	if err := ValidateIndex(index(strings.Repeat("a", IndexNameMaxLength+1), "ITUB4")); err == nil {
+		t.Error("esperado erro para name acima do limite")
+	}
+	if err := ValidateIndex(index("Bancos", strings.Repeat("A", TickerMaxLength+1))); err == nil {
+		t.Error("esperado erro para ticker acima do limite")
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/pkg/indicators"
+)
+
+// GetIndicators calcula os indicadores sobre a série diária do ticker,
+// carregando pregões anteriores a start para que os valores já estejam
+// aquecidos no início da janela. Valores ainda sem histórico suficiente são
+// retornados como nulos.
+func (s *AnalysisService) GetIndicators(ctx context.Context, ticker string, specs []indicators.Spec, start, end *time.Time) (*domain.IndicatorSeries, error) {
+	lookback := 0
+	for _, spec := range specs {
+		if l := spec.Lookback(); l > lookback {
+			lookback = l
+		}
+	}
+
+	bars, first, err := queryDailyBars(ctx, s.pool, ticker, start, end, lookback)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bars) == first {
+		return nil, ErrNotFound
+	}
+
+	window := bars[first:]
+	series := &domain.IndicatorSeries{
+		Ticker:     ticker,
+		Dates:      make([]time.Time, len(window)),
+		Close:      make([]float64, len(window)),
+		Indicators: make(map[string]map[string][]*float64, len(specs)),
+	}
+
+	for i, bar := range window {
+		series.Dates[i] = bar.Date
+		series.Close[i] = bar.Close
+	}
+
+	for _, spec := range specs {
+		outputs := make(map[string][]*float64)
+		for name, values := range indicators.Compute(spec, bars) {
+			outputs[name] = toNullable(values[first:])
+		}
+		series.Indicators[spec.Key()] = outputs
+	}
+
+	return series, nil
+}
+
+func toNullable(values []float64) []*float64 {
+	out := make([]*float64, len(values))
+	for i, v := range values {
+		out[i] = nullableFloat(v)
+	}
+	return out
+}
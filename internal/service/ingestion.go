@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/jeovahfialho/b3-analyzer/internal/ingestion"
 	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
@@ -23,6 +24,7 @@ func NewIngestionService(parser *ingestion.Parser, loader *ingestion.BulkLoader)
 type ProcessFileResult struct {
 	FilePath     string
 	RecordsCount int64
+	Dates        []time.Time
 	Errors       []error
 }
 
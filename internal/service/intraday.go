@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	IntradayDefaultDays   = 20
+	IntradayDefaultBucket = "5m"
+
+	intradayCacheTTL = 24 * time.Hour
+)
+
+func intradayCacheKey(ticker string, days int, bucket string) string {
+	if ticker == "" {
+		ticker = "market"
+	}
+	return fmt.Sprintf("intraday:%s:%d:%s", ticker, days, bucket)
+}
+
+// GetIntradayProfile calcula a sazonalidade intradiária nos últimos days
+// pregões. Com ticker vazio, agrega o mercado inteiro: volume e negócios
+// somados e o retorno absoluto médio entre os tickers.
+func (s *CandleService) GetIntradayProfile(ctx context.Context, ticker string, days int, bucket string) (*domain.IntradayProfile, error) {
+	step, err := ParseCandleInterval(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := intradayCacheKey(ticker, days, bucket)
+	if s.cache != nil {
+		var cached domain.IntradayProfile
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			metrics.RecordCacheHit()
+			cached.CacheHit = true
+			return &cached, nil
+		}
+		metrics.RecordCacheMiss()
+	}
+
+	profile, err := s.queryIntradayProfile(ctx, ticker, days, step)
+	if err != nil {
+		return nil, err
+	}
+	profile.Bucket = bucket
+
+	if s.cache != nil {
+		if err := s.cache.Set(ctx, cacheKey, profile, intradayCacheTTL); err != nil {
+			logger.Warn("erro ao salvar perfil intradiário no cache", zap.Error(err))
+		}
+	}
+
+	return profile, nil
+}
+
+// WarmIntradayProfiles descarta os perfis em cache e recalcula o perfil do
+// mercado com os parâmetros padrão. Deve rodar após cada carga.
+func (s *CandleService) WarmIntradayProfiles(ctx context.Context) error {
+	if s.cache == nil {
+		return nil
+	}
+
+	if err := s.cache.DeletePattern(ctx, "intraday:*"); err != nil {
+		return fmt.Errorf("erro ao invalidar perfis intradiários: %w", err)
+	}
+
+	_, err := s.GetIntradayProfile(ctx, "", IntradayDefaultDays, IntradayDefaultBucket)
+	return err
+}
+
+func (s *CandleService) queryIntradayProfile(ctx context.Context, ticker string, days int, step time.Duration) (*domain.IntradayProfile, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("intraday_profile"))
+
+	args := []interface{}{days, int64(step.Seconds())}
+	sessionFilter, tradeFilter := "", ""
+	if ticker != "" {
+		args = append(args, ticker)
+		sessionFilter = "WHERE codigo_instrumento = $3"
+		tradeFilter = "AND codigo_instrumento = $3"
+	}
+
+	query := fmt.Sprintf(`
+        WITH sessions AS (
+            SELECT DISTINCT data_negocio
+            FROM daily_aggregations
+            %s
+            ORDER BY data_negocio DESC
+            LIMIT $1
+        ),
+        bucketed AS (
+            SELECT
+                codigo_instrumento,
+                data_negocio,
+                (FLOOR(EXTRACT(EPOCH FROM hora_fechamento) / $2) * $2)::bigint as bucket,
+                SUM(quantidade_negociada) as volume,
+                COUNT(*) as trades,
+                (ARRAY_AGG(preco_negocio ORDER BY hora_fechamento, id))[1] as open_price,
+                (ARRAY_AGG(preco_negocio ORDER BY hora_fechamento DESC, id DESC))[1] as close_price
+            FROM trades
+            WHERE data_negocio IN (SELECT data_negocio FROM sessions)
+            %s
+            GROUP BY codigo_instrumento, data_negocio, bucket
+        ),
+        returns AS (
+            SELECT
+                *,
+                ABS(close_price / NULLIF(COALESCE(
+                    LAG(close_price) OVER (PARTITION BY codigo_instrumento, data_negocio ORDER BY bucket),
+                    open_price), 0) - 1) * 100 as abs_return
+            FROM bucketed
+        ),
+        by_session AS (
+            SELECT
+                data_negocio,
+                bucket,
+                SUM(volume) as volume,
+                SUM(trades) as trades,
+                AVG(abs_return) as abs_return
+            FROM returns
+            GROUP BY data_negocio, bucket
+        ),
+        shares AS (
+            SELECT
+                bucket,
+                volume::float8 / NULLIF(SUM(volume) OVER (PARTITION BY data_negocio), 0) as volume_share,
+                trades::float8 / NULLIF(SUM(trades) OVER (PARTITION BY data_negocio), 0) as trade_share,
+                abs_return::float8 as abs_return
+            FROM by_session
+        )
+        SELECT
+            bucket,
+            COALESCE(SUM(volume_share), 0) as volume_share,
+            COALESCE(SUM(trade_share), 0) as trade_share,
+            COALESCE(AVG(abs_return), 0) as abs_return,
+            COUNT(*) as sessions,
+            (SELECT COUNT(*) FROM sessions) as total_sessions,
+            (SELECT MIN(data_negocio) FROM sessions) as start_date,
+            (SELECT MAX(data_negocio) FROM sessions) as end_date
+        FROM shares
+        GROUP BY bucket
+        ORDER BY bucket
+    `, sessionFilter, tradeFilter)
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("intraday_profile", "error").Inc()
+		return nil, fmt.Errorf("erro ao buscar perfil intradiário: %w", err)
+	}
+	defer rows.Close()
+
+	profile := &domain.IntradayProfile{
+		Ticker:  ticker,
+		Days:    days,
+		Buckets: []domain.IntradayBucket{},
+	}
+
+	var cumulative float64
+	for rows.Next() {
+		var item domain.IntradayBucket
+		var bucket int64
+		var volumeShare, tradeShare float64
+
+		err := rows.Scan(
+			&bucket,
+			&volumeShare,
+			&tradeShare,
+			&item.AvgAbsReturn,
+			&item.Sessions,
+			&profile.Sessions,
+			&profile.StartDate,
+			&profile.EndDate,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear perfil intradiário: %w", err)
+		}
+
+		// somas divididas pelo total de pregões: faixas sem negócios contam como zero
+		item.VolumeShare = volumeShare / float64(profile.Sessions) * 100
+		item.TradeShare = tradeShare / float64(profile.Sessions) * 100
+		cumulative += item.VolumeShare
+		item.CumulativeVolumeShare = cumulative
+		item.Time = fmt.Sprintf("%02d:%02d", bucket/3600, bucket%3600/60)
+
+		profile.Buckets = append(profile.Buckets, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar perfil intradiário: %w", err)
+	}
+
+	if len(profile.Buckets) == 0 {
+		return nil, ErrNotFound
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("intraday_profile", "success").Inc()
+	return profile, nil
+}
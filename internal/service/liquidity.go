@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// liquiditySortDescending indica, por métrica, se valores maiores são mais
+// líquidos. O ranking vai do mais para o menos líquido.
+var liquiditySortDescending = map[string]bool{
+	"amihud":                     false,
+	"roll_spread_bps":            false,
+	"median_inter_trade_seconds": false,
+	"trades_per_minute":          true,
+	"avg_trade_size":             true,
+	"financial_volume":           true,
+}
+
+func liquiditySortValue(m domain.LiquidityMetrics, sortBy string) *float64 {
+	var v float64
+	switch sortBy {
+	case "amihud":
+		return m.Amihud
+	case "roll_spread_bps":
+		return m.RollSpreadBps
+	case "median_inter_trade_seconds":
+		return m.MedianInterTradeSeconds
+	case "trades_per_minute":
+		v = m.TradesPerMinute
+	case "avg_trade_size":
+		v = float64(m.AvgTradeSize)
+	default:
+		v = m.FinancialVolume.InexactFloat64()
+	}
+	return &v
+}
+
+func ValidLiquiditySort(sortBy string) bool {
+	_, ok := liquiditySortDescending[sortBy]
+	return ok
+}
+
+// GetTickerLiquidity calcula as métricas de liquidez de cada pregão do
+// ticker no período.
+func (s *AnalysisService) GetTickerLiquidity(ctx context.Context, ticker string, startDate, endDate time.Time) ([]domain.LiquidityMetrics, error) {
+	result, err := s.queryLiquidity(ctx, ticker, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return result, nil
+}
+
+// GetLiquidityRanking ranqueia todos os tickers do pregão por sortBy. O
+// corte transversal completo fica em cache por data; a paginação é feita
+// sobre ele.
+func (s *AnalysisService) GetLiquidityRanking(ctx context.Context, date time.Time, sortBy string, page, pageSize int) (*domain.LiquidityRanking, error) {
+	descending, ok := liquiditySortDescending[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("ordenação de liquidez inválida: %s", sortBy)
+	}
+
+	var all []domain.LiquidityMetrics
+	cacheHit := false
+	cacheKey := fmt.Sprintf("liquidity:%s", date.Format("2006-01-02"))
+
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &all); err == nil {
+			metrics.RecordCacheHit()
+			cacheHit = true
+		} else {
+			metrics.RecordCacheMiss()
+		}
+	}
+
+	if !cacheHit {
+		var err error
+		if all, err = s.queryLiquidity(ctx, "", date, date); err != nil {
+			return nil, err
+		}
+
+		if s.cache != nil && len(all) > 0 {
+			if err := s.cache.Set(ctx, cacheKey, all); err != nil {
+				logger.Warn("erro ao salvar liquidez no cache", zap.Error(err))
+			}
+		}
+	}
+
+	if len(all) == 0 {
+		return nil, ErrNotFound
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		a, b := liquiditySortValue(all[i], sortBy), liquiditySortValue(all[j], sortBy)
+		switch {
+		case a == nil || b == nil:
+			// valores ausentes ficam sempre no fim
+			return a != nil && b == nil
+		case *a == *b:
+			return all[i].Ticker < all[j].Ticker
+		case descending:
+			return *a > *b
+		default:
+			return *a < *b
+		}
+	})
+
+	result := &domain.LiquidityRanking{
+		Date:       date,
+		SortBy:     sortBy,
+		Data:       []domain.LiquidityMetrics{},
+		TotalCount: len(all),
+		Page:       page,
+		PageSize:   pageSize,
+		CacheHit:   cacheHit,
+	}
+
+	offset := (page - 1) * pageSize
+	for i := offset; i < len(all) && i < offset+pageSize; i++ {
+		item := all[i]
+		item.Rank = i + 1
+		result.Data = append(result.Data, item)
+	}
+	result.HasMore = offset+len(result.Data) < len(all)
+
+	return result, nil
+}
+
+// queryLiquidity calcula as métricas por ticker e pregão; com ticker vazio,
+// para todos os tickers do período. O retorno do primeiro pregão usa o
+// fechamento anterior ao período.
+func (s *AnalysisService) queryLiquidity(ctx context.Context, ticker string, startDate, endDate time.Time) ([]domain.LiquidityMetrics, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("liquidity"))
+
+	args := []interface{}{startDate, endDate}
+	tickerFilter := ""
+	if ticker != "" {
+		args = append(args, ticker)
+		tickerFilter = "AND codigo_instrumento = $3"
+	}
+
+	query := fmt.Sprintf(`
+        WITH changes AS (
+            SELECT
+                codigo_instrumento,
+                data_negocio,
+                hora_fechamento,
+                preco_negocio - LAG(preco_negocio) OVER w as dp,
+                EXTRACT(EPOCH FROM hora_fechamento - LAG(hora_fechamento) OVER w)::float8 as gap,
+                id
+            FROM trades
+            WHERE data_negocio BETWEEN $1 AND $2
+            %[1]s
+            WINDOW w AS (PARTITION BY codigo_instrumento, data_negocio ORDER BY hora_fechamento, id)
+        ),
+        pairs AS (
+            SELECT
+                *,
+                LAG(dp) OVER (PARTITION BY codigo_instrumento, data_negocio ORDER BY hora_fechamento, id) as dp_prev
+            FROM changes
+        ),
+        intraday AS (
+            SELECT
+                codigo_instrumento,
+                data_negocio,
+                COVAR_SAMP(dp, dp_prev)::float8 as roll_cov,
+                PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY gap) as median_gap,
+                EXTRACT(EPOCH FROM MAX(hora_fechamento) - MIN(hora_fechamento))::float8 as span
+            FROM pairs
+            GROUP BY codigo_instrumento, data_negocio
+        ),
+        daily AS (
+            SELECT *
+            FROM (
+                SELECT
+                    codigo_instrumento,
+                    data_negocio,
+                    close_price,
+                    LAG(close_price) OVER (PARTITION BY codigo_instrumento ORDER BY data_negocio) as prev_close,
+                    avg_price,
+                    total_volume,
+                    trade_count,
+                    financial_volume
+                FROM daily_aggregations
+                WHERE data_negocio <= $2
+                AND data_negocio >= $1::date - INTERVAL '15 days'
+                %[1]s
+            ) d
+            WHERE data_negocio >= $1
+        )
+        SELECT
+            d.codigo_instrumento,
+            d.data_negocio,
+            d.close_price,
+            d.prev_close,
+            d.avg_price,
+            d.total_volume,
+            d.trade_count,
+            d.financial_volume,
+            i.roll_cov,
+            i.median_gap,
+            COALESCE(i.span, 0)
+        FROM daily d
+        LEFT JOIN intraday i
+            ON i.codigo_instrumento = d.codigo_instrumento
+            AND i.data_negocio = d.data_negocio
+        ORDER BY d.data_negocio, d.codigo_instrumento
+    `, tickerFilter)
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("liquidity", "error").Inc()
+		return nil, fmt.Errorf("erro ao calcular liquidez: %w", err)
+	}
+	defer rows.Close()
+
+	result := []domain.LiquidityMetrics{}
+	for rows.Next() {
+		var row liquidityRow
+		err := rows.Scan(
+			&row.ticker,
+			&row.date,
+			&row.close,
+			&row.prevClose,
+			&row.avgPrice,
+			&row.volume,
+			&row.trades,
+			&row.financial,
+			&row.rollCov,
+			&row.medianGap,
+			&row.span,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear liquidez: %w", err)
+		}
+
+		result = append(result, row.metrics())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar liquidez: %w", err)
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("liquidity", "success").Inc()
+	return result, nil
+}
+
+type liquidityRow struct {
+	ticker    string
+	date      time.Time
+	close     decimal.Decimal
+	prevClose *decimal.Decimal
+	avgPrice  decimal.Decimal
+	volume    int64
+	trades    int
+	financial decimal.Decimal
+	rollCov   *float64
+	medianGap *float64
+	span      float64
+}
+
+func (r liquidityRow) metrics() domain.LiquidityMetrics {
+	m := domain.LiquidityMetrics{
+		Ticker:                  r.ticker,
+		Date:                    r.date,
+		MedianInterTradeSeconds: r.medianGap,
+		TradeCount:              r.trades,
+		FinancialVolume:         r.financial,
+	}
+
+	if r.trades > 0 {
+		m.AvgTradeSize = r.volume / int64(r.trades)
+		m.AvgTradeFinancial = r.financial.Div(decimal.NewFromInt(int64(r.trades))).Round(2)
+
+		// pregão com negócio único ou num só instante conta como um minuto
+		minutes := math.Max(r.span/60, 1)
+		m.TradesPerMinute = float64(r.trades) / minutes
+	}
+
+	if r.prevClose != nil && r.prevClose.IsPositive() {
+		ret := r.close.Div(*r.prevClose).Sub(decimal.NewFromInt(1)).InexactFloat64() * 100
+		m.ReturnPercent = &ret
+
+		if r.financial.IsPositive() {
+			amihud := math.Abs(ret) / (r.financial.InexactFloat64() / 1e6)
+			m.Amihud = &amihud
+		}
+	}
+
+	if r.rollCov != nil && *r.rollCov < 0 {
+		spread := 2 * math.Sqrt(-*r.rollCov)
+		m.RollSpread = &spread
+
+		if r.avgPrice.IsPositive() {
+			bps := spread / r.avgPrice.InexactFloat64() * 10000
+			m.RollSpreadBps = &bps
+		}
+	}
+
+	return m
+}
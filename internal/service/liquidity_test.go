@@ -0,0 +1,47 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestLiquidityRowMetrics(t *testing.T) {
+	prevClose := decimal.NewFromInt(20)
+	rollCov := -0.0001
+	row := liquidityRow{
+		ticker:    "PETR4",
+		close:     decimal.RequireFromString("20.4"),
+		prevClose: &prevClose,
+		avgPrice:  decimal.NewFromInt(20),
+		volume:    50000,
+		trades:    250,
+		financial: decimal.NewFromInt(2000000),
+		rollCov:   &rollCov,
+		span:      7200,
+	}
+
+	m := row.metrics()
+
+	if m.ReturnPercent == nil || math.Abs(*m.ReturnPercent-2) > 1e-9 {
+		t.Fatalf("esperado retorno de 2%%, recebido %v", m.ReturnPercent)
+	}
+	// 2% por R$ 2 milhões
+	if m.Amihud == nil || math.Abs(*m.Amihud-1) > 1e-9 {
+		t.Errorf("esperado Amihud 1, recebido %v", m.Amihud)
+	}
+	// 2 * sqrt(0.0001) = 0.02, ou 10 bps de R$ 20
+	if m.RollSpread == nil || math.Abs(*m.RollSpread-0.02) > 1e-9 || math.Abs(*m.RollSpreadBps-10) > 1e-9 {
+		t.Errorf("spread de Roll inesperado: %v / %v", m.RollSpread, m.RollSpreadBps)
+	}
+	if m.AvgTradeSize != 200 || math.Abs(m.TradesPerMinute-250.0/120) > 1e-9 {
+		t.Errorf("tamanho médio %d e negócios/minuto %f inesperados", m.AvgTradeSize, m.TradesPerMinute)
+	}
+
+	positive := 0.0001
+	row.rollCov = &positive
+	if row.metrics().RollSpread != nil {
+		t.Error("autocovariância positiva não deveria gerar spread de Roll")
+	}
+}
@@ -0,0 +1,508 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"github.com/shopspring/decimal"
+)
+
+type PortfolioService struct {
+	pool *pgxpool.Pool
+}
+
+func NewPortfolioService(pool *pgxpool.Pool) *PortfolioService {
+	return &PortfolioService{pool: pool}
+}
+
+// PortfolioNameMaxLength acompanha a coluna portfolios.name.
+const PortfolioNameMaxLength = 100
+
+func ValidatePortfolioName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name é obrigatório")
+	}
+	if utf8.RuneCountInString(name) > PortfolioNameMaxLength {
+		return fmt.Errorf("name deve ter até %d caracteres", PortfolioNameMaxLength)
+	}
+	return nil
+}
+
+func ValidatePosition(position domain.Position) error {
+	if position.Ticker == "" {
+		return fmt.Errorf("ticker é obrigatório")
+	}
+	if utf8.RuneCountInString(position.Ticker) > TickerMaxLength {
+		return fmt.Errorf("ticker deve ter até %d caracteres", TickerMaxLength)
+	}
+	if position.Quantity == 0 {
+		return fmt.Errorf("quantidade não pode ser zero")
+	}
+	if position.CostBasis.IsNegative() {
+		return fmt.Errorf("cost_basis não pode ser negativo")
+	}
+	if position.TradeDate.IsZero() {
+		return fmt.Errorf("trade_date é obrigatório")
+	}
+	return nil
+}
+
+func (s *PortfolioService) CreatePortfolio(ctx context.Context, portfolio domain.Portfolio) (*domain.Portfolio, error) {
+	if err := ValidatePortfolioName(portfolio.Name); err != nil {
+		return nil, err
+	}
+	for i, position := range portfolio.Positions {
+		if err := ValidatePosition(position); err != nil {
+			return nil, fmt.Errorf("posição %d: %w", i, err)
+		}
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+        INSERT INTO portfolios (name, description)
+        VALUES ($1, NULLIF($2, ''))
+        RETURNING id, created_at, updated_at
+    `, portfolio.Name, portfolio.Description).Scan(&portfolio.ID, &portfolio.CreatedAt, &portfolio.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar carteira: %w", err)
+	}
+
+	for i := range portfolio.Positions {
+		if err := insertPosition(ctx, tx, portfolio.ID, &portfolio.Positions[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("erro no commit: %w", err)
+	}
+
+	return &portfolio, nil
+}
+
+func (s *PortfolioService) ListPortfolios(ctx context.Context) ([]domain.Portfolio, error) {
+	rows, err := s.pool.Query(ctx, `
+        SELECT id, name, COALESCE(description, ''), created_at, updated_at
+        FROM portfolios
+        ORDER BY id
+    `)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar carteiras: %w", err)
+	}
+	defer rows.Close()
+
+	portfolios := []domain.Portfolio{}
+	for rows.Next() {
+		var p domain.Portfolio
+		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("erro ao escanear carteira: %w", err)
+		}
+		portfolios = append(portfolios, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar carteiras: %w", err)
+	}
+
+	return portfolios, nil
+}
+
+func (s *PortfolioService) GetPortfolio(ctx context.Context, id int64) (*domain.Portfolio, error) {
+	var p domain.Portfolio
+
+	err := s.pool.QueryRow(ctx, `
+        SELECT id, name, COALESCE(description, ''), created_at, updated_at
+        FROM portfolios
+        WHERE id = $1
+    `, id).Scan(&p.ID, &p.Name, &p.Description, &p.CreatedAt, &p.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar carteira: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+        SELECT id, portfolio_id, codigo_instrumento, quantity, cost_basis, trade_date, created_at
+        FROM portfolio_positions
+        WHERE portfolio_id = $1
+        ORDER BY trade_date, id
+    `, id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar posições: %w", err)
+	}
+	defer rows.Close()
+
+	p.Positions = []domain.Position{}
+	for rows.Next() {
+		var position domain.Position
+		err := rows.Scan(
+			&position.ID,
+			&position.PortfolioID,
+			&position.Ticker,
+			&position.Quantity,
+			&position.CostBasis,
+			&position.TradeDate,
+			&position.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear posição: %w", err)
+		}
+		p.Positions = append(p.Positions, position)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar posições: %w", err)
+	}
+
+	return &p, nil
+}
+
+func (s *PortfolioService) UpdatePortfolio(ctx context.Context, id int64, name, description string) error {
+	if err := ValidatePortfolioName(name); err != nil {
+		return err
+	}
+
+	tag, err := s.pool.Exec(ctx, `
+        UPDATE portfolios
+        SET name = $2, description = NULLIF($3, ''), updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1
+    `, id, name, description)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar carteira: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *PortfolioService) DeletePortfolio(ctx context.Context, id int64) error {
+	tag, err := s.pool.Exec(ctx, "DELETE FROM portfolios WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("erro ao remover carteira: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *PortfolioService) AddPosition(ctx context.Context, portfolioID int64, position domain.Position) (*domain.Position, error) {
+	if err := ValidatePosition(position); err != nil {
+		return nil, err
+	}
+
+	var exists bool
+	if err := s.pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM portfolios WHERE id = $1)", portfolioID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("erro ao buscar carteira: %w", err)
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	if err := insertPosition(ctx, s.pool, portfolioID, &position); err != nil {
+		return nil, err
+	}
+
+	return &position, nil
+}
+
+func (s *PortfolioService) UpdatePosition(ctx context.Context, portfolioID int64, position domain.Position) error {
+	if err := ValidatePosition(position); err != nil {
+		return err
+	}
+
+	tag, err := s.pool.Exec(ctx, `
+        UPDATE portfolio_positions
+        SET codigo_instrumento = $3, quantity = $4, cost_basis = $5, trade_date = $6
+        WHERE id = $1 AND portfolio_id = $2
+    `, position.ID, portfolioID, position.Ticker, position.Quantity, position.CostBasis, position.TradeDate)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar posição: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *PortfolioService) DeletePosition(ctx context.Context, portfolioID, positionID int64) error {
+	tag, err := s.pool.Exec(ctx,
+		"DELETE FROM portfolio_positions WHERE id = $1 AND portfolio_id = $2", positionID, portfolioID)
+	if err != nil {
+		return fmt.Errorf("erro ao remover posição: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+func insertPosition(ctx context.Context, db rowQuerier, portfolioID int64, position *domain.Position) error {
+	err := db.QueryRow(ctx, `
+        INSERT INTO portfolio_positions (portfolio_id, codigo_instrumento, quantity, cost_basis, trade_date)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at
+    `, portfolioID, position.Ticker, position.Quantity, position.CostBasis, position.TradeDate).Scan(&position.ID, &position.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao criar posição: %w", err)
+	}
+
+	position.PortfolioID = portfolioID
+	return nil
+}
+
+type positionMark struct {
+	position  domain.Position
+	priceDate *time.Time
+	close     *decimal.Decimal
+	prevDate  *time.Time
+	prevClose *decimal.Decimal
+}
+
+// GetValuation marca as posições abertas até date pelo último fechamento
+// disponível (o do pregão, ou o mais recente antes dele se o ticker não
+// negociou). A série de NAV cobre os pregões de start até date; sem start,
+// começa na data da primeira posição.
+func (s *PortfolioService) GetValuation(ctx context.Context, id int64, date, start *time.Time) (*domain.PortfolioValuation, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("portfolio_valuation"))
+
+	portfolio, err := s.GetPortfolio(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if date == nil {
+		var latest *time.Time
+		if err := s.pool.QueryRow(ctx, "SELECT MAX(data_negocio) FROM daily_aggregations").Scan(&latest); err != nil {
+			return nil, fmt.Errorf("erro ao buscar último pregão: %w", err)
+		}
+		if latest == nil {
+			return nil, ErrNotFound
+		}
+		date = latest
+	}
+
+	marks, err := s.queryMarks(ctx, id, *date)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("portfolio_valuation", "error").Inc()
+		return nil, err
+	}
+
+	valuation := valuePositions(marks)
+	valuation.PortfolioID = portfolio.ID
+	valuation.Name = portfolio.Name
+	valuation.Date = *date
+
+	if start == nil && len(portfolio.Positions) > 0 {
+		first := portfolio.Positions[0].TradeDate
+		start = &first
+	}
+
+	valuation.NAV = []domain.NAVPoint{}
+	if start != nil {
+		if valuation.NAV, err = s.queryNAV(ctx, id, *start, *date); err != nil {
+			metrics.DatabaseQueries.WithLabelValues("portfolio_valuation", "error").Inc()
+			return nil, err
+		}
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("portfolio_valuation", "success").Inc()
+	return valuation, nil
+}
+
+func (s *PortfolioService) queryMarks(ctx context.Context, id int64, date time.Time) ([]positionMark, error) {
+	query := `
+        SELECT
+            p.id, p.codigo_instrumento, p.quantity, p.cost_basis, p.trade_date,
+            c.data_negocio, c.close_price,
+            prev.data_negocio, prev.close_price
+        FROM portfolio_positions p
+        LEFT JOIN LATERAL (
+            SELECT data_negocio, close_price
+            FROM daily_aggregations
+            WHERE codigo_instrumento = p.codigo_instrumento
+            AND data_negocio <= $2
+            ORDER BY data_negocio DESC
+            LIMIT 1
+        ) c ON TRUE
+        LEFT JOIN LATERAL (
+            SELECT data_negocio, close_price
+            FROM daily_aggregations
+            WHERE codigo_instrumento = p.codigo_instrumento
+            AND data_negocio < $2
+            ORDER BY data_negocio DESC
+            LIMIT 1
+        ) prev ON TRUE
+        WHERE p.portfolio_id = $1
+        AND p.trade_date <= $2
+        ORDER BY p.trade_date, p.id
+    `
+
+	rows, err := s.pool.Query(ctx, query, id, date)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar fechamentos das posições: %w", err)
+	}
+	defer rows.Close()
+
+	var marks []positionMark
+	for rows.Next() {
+		var m positionMark
+		err := rows.Scan(
+			&m.position.ID,
+			&m.position.Ticker,
+			&m.position.Quantity,
+			&m.position.CostBasis,
+			&m.position.TradeDate,
+			&m.priceDate,
+			&m.close,
+			&m.prevDate,
+			&m.prevClose,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear posição: %w", err)
+		}
+		m.position.PortfolioID = id
+		marks = append(marks, m)
+	}
+
+	return marks, rows.Err()
+}
+
+// valuePositions calcula valor de mercado e P&L de cada posição. O P&L do
+// dia parte do fechamento anterior, ou do custo se a posição foi aberta
+// depois dele. Posições sem nenhum fechamento ficam fora dos totais.
+func valuePositions(marks []positionMark) *domain.PortfolioValuation {
+	valuation := &domain.PortfolioValuation{
+		Cost:        decimal.Zero,
+		MarketValue: decimal.Zero,
+		DailyPnL:    decimal.Zero,
+		TotalPnL:    decimal.Zero,
+		Positions:   make([]domain.PositionValuation, 0, len(marks)),
+	}
+
+	gross := decimal.Zero
+
+	for _, m := range marks {
+		quantity := decimal.NewFromInt(m.position.Quantity)
+
+		pv := domain.PositionValuation{
+			PositionID: m.position.ID,
+			Ticker:     m.position.Ticker,
+			Quantity:   m.position.Quantity,
+			CostBasis:  m.position.CostBasis,
+			TradeDate:  m.position.TradeDate,
+			PriceDate:  m.priceDate,
+			Close:      m.close,
+			Cost:       m.position.CostBasis.Mul(quantity),
+		}
+
+		if m.close == nil {
+			valuation.Unpriced = append(valuation.Unpriced, m.position.Ticker)
+			valuation.Positions = append(valuation.Positions, pv)
+			continue
+		}
+
+		reference := m.position.CostBasis
+		if m.prevClose != nil && m.prevDate != nil && !m.prevDate.Before(m.position.TradeDate) {
+			reference = *m.prevClose
+			pv.PreviousClose = m.prevClose
+		}
+
+		pv.MarketValue = m.close.Mul(quantity)
+		pv.DailyPnL = m.close.Sub(reference).Mul(quantity)
+		pv.TotalPnL = pv.MarketValue.Sub(pv.Cost)
+		if !pv.Cost.IsZero() {
+			pv.TotalPnLPercent = pv.TotalPnL.Div(pv.Cost.Abs()).InexactFloat64() * 100
+		}
+
+		valuation.Cost = valuation.Cost.Add(pv.Cost)
+		valuation.MarketValue = valuation.MarketValue.Add(pv.MarketValue)
+		valuation.DailyPnL = valuation.DailyPnL.Add(pv.DailyPnL)
+		valuation.TotalPnL = valuation.TotalPnL.Add(pv.TotalPnL)
+		gross = gross.Add(pv.MarketValue.Abs())
+
+		valuation.Positions = append(valuation.Positions, pv)
+	}
+
+	// pesos sobre o valor bruto para que posições vendidas não distorçam
+	if gross.IsPositive() {
+		for i := range valuation.Positions {
+			valuation.Positions[i].Weight = valuation.Positions[i].MarketValue.Div(gross).InexactFloat64()
+		}
+	}
+
+	if !valuation.Cost.IsZero() {
+		valuation.TotalPnLPercent = valuation.TotalPnL.Div(valuation.Cost.Abs()).InexactFloat64() * 100
+	}
+
+	return valuation
+}
+
+func (s *PortfolioService) queryNAV(ctx context.Context, id int64, start, end time.Time) ([]domain.NAVPoint, error) {
+	query := `
+        WITH sessions AS (
+            SELECT DISTINCT data_negocio
+            FROM daily_aggregations
+            WHERE data_negocio BETWEEN $2 AND $3
+        )
+        SELECT
+            s.data_negocio,
+            SUM(p.quantity * c.close_price) as market_value,
+            SUM(p.quantity * p.cost_basis) as cost
+        FROM sessions s
+        JOIN portfolio_positions p
+            ON p.portfolio_id = $1
+            AND p.trade_date <= s.data_negocio
+        CROSS JOIN LATERAL (
+            SELECT close_price
+            FROM daily_aggregations
+            WHERE codigo_instrumento = p.codigo_instrumento
+            AND data_negocio <= s.data_negocio
+            ORDER BY data_negocio DESC
+            LIMIT 1
+        ) c
+        GROUP BY s.data_negocio
+        ORDER BY s.data_negocio
+    `
+
+	rows, err := s.pool.Query(ctx, query, id, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar série de NAV: %w", err)
+	}
+	defer rows.Close()
+
+	nav := []domain.NAVPoint{}
+	for rows.Next() {
+		var point domain.NAVPoint
+		if err := rows.Scan(&point.Date, &point.MarketValue, &point.Cost); err != nil {
+			return nil, fmt.Errorf("erro ao escanear NAV: %w", err)
+		}
+		point.PnL = point.MarketValue.Sub(point.Cost)
+		nav = append(nav, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar NAV: %w", err)
+	}
+
+	return nav, nil
+}
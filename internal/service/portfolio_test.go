@@ -0,0 +1,84 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestValuePositions(t *testing.T) {
+	day := time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC)
+	prevDay := day.AddDate(0, 0, -1)
+
+	price := func(v string) *decimal.Decimal {
+		d := decimal.RequireFromString(v)
+		return &d
+	}
+
+	marks := []positionMark{
+		{
+			// aberta antes do pregão anterior: P&L do dia parte do fechamento anterior
+			position:  domain.Position{ID: 1, Ticker: "PETR4", Quantity: 100, CostBasis: decimal.NewFromInt(30), TradeDate: prevDay.AddDate(0, 0, -5)},
+			priceDate: &day, close: price("32"), prevDate: &prevDay, prevClose: price("31"),
+		},
+		{
+			// aberta no próprio pregão: P&L do dia parte do custo
+			position:  domain.Position{ID: 2, Ticker: "VALE3", Quantity: 50, CostBasis: decimal.NewFromInt(60), TradeDate: day},
+			priceDate: &day, close: price("62"), prevDate: &prevDay, prevClose: price("58"),
+		},
+		{
+			position: domain.Position{ID: 3, Ticker: "XPTO3", Quantity: 10, CostBasis: decimal.NewFromInt(5), TradeDate: day},
+		},
+	}
+
+	v := valuePositions(marks)
+
+	if !v.MarketValue.Equal(decimal.NewFromInt(3200 + 3100)) {
+		t.Errorf("valor de mercado inesperado: %s", v.MarketValue)
+	}
+	if !v.DailyPnL.Equal(decimal.NewFromInt(100 + 100)) {
+		t.Errorf("P&L do dia inesperado: %s", v.DailyPnL)
+	}
+	if !v.TotalPnL.Equal(decimal.NewFromInt(200 + 100)) {
+		t.Errorf("P&L total inesperado: %s", v.TotalPnL)
+	}
+	if len(v.Unpriced) != 1 || v.Unpriced[0] != "XPTO3" {
+		t.Errorf("esperado XPTO3 sem preço, recebido %v", v.Unpriced)
+	}
+
+	weights := v.Positions[0].Weight + v.Positions[1].Weight
+	if weights < 0.999999 || weights > 1.000001 {
+		t.Errorf("pesos deveriam somar 1, somam %f", weights)
+	}
+}
+
+func TestValidatePortfolioName(t *testing.T) {
+	if err := ValidatePortfolioName(""); err == nil {
+		t.Error("esperado erro para nome vazio")
+	}
+	if err := ValidatePortfolioName(strings.Repeat("ç", PortfolioNameMaxLength)); err != nil {
+		t.Errorf("erro inesperado no limite: %v", err)
+	}
+	if err := ValidatePortfolioName(strings.Repeat("a", PortfolioNameMaxLength+1)); err == nil {
+		t.Error("esperado erro para nome acima do limite")
+	}
+}
+
+func TestValidatePositionTickerLength(t *testing.T) {
+	position := domain.Position{
+		Ticker:    strings.Repeat("A", TickerMaxLength+1),
+		Quantity:  100,
+		TradeDate: time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC),
+	}
+	if err := ValidatePosition(position); err == nil {
+		t.Error("esperado erro para ticker acima do limite")
+	}
+
+	position.Ticker = "PETR4"
+	if err := ValidatePosition(position); err != nil {
+		t.Errorf("erro inesperado: %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ProfileModeTick  = "tick"
+	ProfileModeFixed = "fixed"
+
+	valueAreaShare = 0.70
+)
+
+// DefaultTickSize é o incremento mínimo de preço das ações no mercado à
+// vista da B3.
+var DefaultTickSize = decimal.RequireFromString("0.01")
+
+type priceLevel struct {
+	price  decimal.Decimal
+	volume int64
+	trades int
+}
+
+// GetVolumeProfile agrupa os negócios do período por preço. No modo tick
+// as faixas são múltiplos do tick alinhados a ele, com o menor número de
+// ticks por faixa que caiba em bins; no modo fixed o intervalo entre a
+// mínima e a máxima é dividido em bins faixas iguais.
+func (s *CandleService) GetVolumeProfile(ctx context.Context, ticker string, startDate, endDate time.Time, bins int, mode string, tickSize decimal.Decimal) (*domain.VolumeProfile, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("volume_profile"))
+
+	query := `
+        SELECT
+            preco_negocio,
+            SUM(quantidade_negociada) as volume,
+            COUNT(*) as trades
+        FROM trades
+        WHERE codigo_instrumento = $1
+        AND data_negocio BETWEEN $2 AND $3
+        GROUP BY preco_negocio
+        ORDER BY preco_negocio
+    `
+
+	rows, err := s.pool.Query(ctx, query, ticker, startDate, endDate)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("volume_profile", "error").Inc()
+		return nil, fmt.Errorf("erro ao buscar perfil de volume: %w", err)
+	}
+	defer rows.Close()
+
+	var levels []priceLevel
+	for rows.Next() {
+		var level priceLevel
+		if err := rows.Scan(&level.price, &level.volume, &level.trades); err != nil {
+			return nil, fmt.Errorf("erro ao escanear perfil de volume: %w", err)
+		}
+		levels = append(levels, level)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar perfil de volume: %w", err)
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("volume_profile", "success").Inc()
+
+	if len(levels) == 0 {
+		return nil, ErrNotFound
+	}
+
+	profile := buildVolumeProfile(levels, bins, mode, tickSize)
+	profile.Ticker = ticker
+	profile.StartDate = startDate
+	profile.EndDate = endDate
+
+	return profile, nil
+}
+
+// buildVolumeProfile espera os níveis ordenados por preço.
+func buildVolumeProfile(levels []priceLevel, bins int, mode string, tickSize decimal.Decimal) *domain.VolumeProfile {
+	minPrice, maxPrice := levels[0].price, levels[len(levels)-1].price
+	priceRange := maxPrice.Sub(minPrice)
+
+	var start, width decimal.Decimal
+	count := bins
+	switch {
+	case mode == ProfileModeFixed && priceRange.IsPositive():
+		start = minPrice
+		width = priceRange.Div(decimal.NewFromInt(int64(bins)))
+	default:
+		ticks := priceRange.Div(tickSize).Floor().IntPart() + 1
+		ticksPerBucket := (ticks + int64(bins) - 1) / int64(bins)
+		width = tickSize.Mul(decimal.NewFromInt(ticksPerBucket))
+		start = minPrice.Div(width).Floor().Mul(width)
+		count = int(maxPrice.Sub(start).Div(width).Floor().IntPart()) + 1
+	}
+
+	profile := &domain.VolumeProfile{
+		Mode:        mode,
+		BucketWidth: width,
+		Buckets:     make([]domain.VolumeProfileBucket, count),
+	}
+
+	for i := range profile.Buckets {
+		low := start.Add(width.Mul(decimal.NewFromInt(int64(i))))
+		high := low.Add(width)
+		if mode != ProfileModeFixed {
+			// no modo tick a faixa termina no último tick que ela contém
+			high = high.Sub(tickSize)
+		}
+		profile.Buckets[i].PriceLow = low
+		profile.Buckets[i].PriceHigh = high
+	}
+
+	for _, level := range levels {
+		i := int(level.price.Sub(start).Div(width).Floor().IntPart())
+		if i >= count {
+			i = count - 1
+		}
+		profile.Buckets[i].Volume += level.volume
+		profile.Buckets[i].Trades += level.trades
+		profile.TotalVolume += level.volume
+		profile.TotalTrades += level.trades
+	}
+
+	poc := 0
+	for i, bucket := range profile.Buckets {
+		if profile.TotalVolume > 0 {
+			profile.Buckets[i].Percent = float64(bucket.Volume) / float64(profile.TotalVolume) * 100
+		}
+		if bucket.Volume > profile.Buckets[poc].Volume {
+			poc = i
+		}
+	}
+
+	low, high := valueArea(profile.Buckets, poc, valueAreaShare)
+	profile.PointOfControl = profile.Buckets[poc].PriceLow.Add(profile.Buckets[poc].PriceHigh).Div(decimal.NewFromInt(2))
+	profile.ValueAreaLow = profile.Buckets[low].PriceLow
+	profile.ValueAreaHigh = profile.Buckets[high].PriceHigh
+
+	var areaVolume int64
+	for _, bucket := range profile.Buckets[low : high+1] {
+		areaVolume += bucket.Volume
+	}
+	if profile.TotalVolume > 0 {
+		profile.ValueAreaPercent = float64(areaVolume) / float64(profile.TotalVolume) * 100
+	}
+
+	return profile
+}
+
+// valueArea expande a partir do POC incorporando, a cada passo, a faixa
+// vizinha de maior volume até atingir share do volume total.
+func valueArea(buckets []domain.VolumeProfileBucket, poc int, share float64) (int, int) {
+	var total int64
+	for _, bucket := range buckets {
+		total += bucket.Volume
+	}
+
+	target := float64(total) * share
+	low, high := poc, poc
+	volume := buckets[poc].Volume
+
+	for float64(volume) < target && (low > 0 || high < len(buckets)-1) {
+		var below, above int64 = -1, -1
+		if low > 0 {
+			below = buckets[low-1].Volume
+		}
+		if high < len(buckets)-1 {
+			above = buckets[high+1].Volume
+		}
+
+		if above >= below {
+			high++
+			volume += above
+		} else {
+			low--
+			volume += below
+		}
+	}
+
+	return low, high
+}
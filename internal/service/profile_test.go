@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestBuildVolumeProfile(t *testing.T) {
+	level := func(price string, volume int64) priceLevel {
+		return priceLevel{price: decimal.RequireFromString(price), volume: volume, trades: 1}
+	}
+
+	levels := []priceLevel{
+		level("10.00", 100),
+		level("10.01", 200),
+		level("10.02", 600),
+		level("10.03", 300),
+		level("10.04", 100),
+		level("10.05", 50),
+	}
+
+	profile := buildVolumeProfile(levels, 3, ProfileModeTick, DefaultTickSize)
+
+	if len(profile.Buckets) != 3 || !profile.BucketWidth.Equal(decimal.RequireFromString("0.02")) {
+		t.Fatalf("esperado 3 faixas de 0.02, recebido %d de %s", len(profile.Buckets), profile.BucketWidth)
+	}
+	if profile.Buckets[1].Volume != 900 || profile.TotalVolume != 1350 || profile.TotalTrades != 6 {
+		t.Errorf("volumes inesperados: %+v", profile.Buckets)
+	}
+	if !profile.PointOfControl.Equal(decimal.RequireFromString("10.025")) {
+		t.Errorf("esperado POC 10.025, recebido %s", profile.PointOfControl)
+	}
+	// 900 (66,7%) não basta; a faixa de baixo (300) vence a de cima (150)
+	if !profile.ValueAreaLow.Equal(decimal.RequireFromString("10.00")) || !profile.ValueAreaHigh.Equal(decimal.RequireFromString("10.03")) {
+		t.Errorf("value area inesperada: %s a %s", profile.ValueAreaLow, profile.ValueAreaHigh)
+	}
+
+	fixed := buildVolumeProfile(levels, 5, ProfileModeFixed, DefaultTickSize)
+	if len(fixed.Buckets) != 5 || fixed.Buckets[4].Volume != 150 {
+		t.Errorf("faixas fixas inesperadas: %+v", fixed.Buckets)
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	VolumeMetricShares    = "shares"
+	VolumeMetricFinancial = "financial"
+)
+
+var volumeMetricColumns = map[string]string{
+	VolumeMetricShares:    "total_volume",
+	VolumeMetricFinancial: "financial_volume",
+}
+
+func ValidVolumeMetric(metric string) bool {
+	_, ok := volumeMetricColumns[metric]
+	return ok
+}
+
+// GetVolumeRanking ranqueia os tickers pelo volume somado nos últimos
+// q.Window pregões do mercado. A variação compara a média diária da janela
+// com a média diária dos q.Compare pregões imediatamente anteriores; as
+// médias dividem pelo número de pregões do mercado, não do ticker. Com a
+// métrica financial os volumes são em reais, truncados.
+func (s *AnalysisService) GetVolumeRanking(ctx context.Context, q domain.VolumeRankingQuery) (*domain.VolumeRankingResult, error) {
+	column, ok := volumeMetricColumns[q.Metric]
+	if !ok {
+		return nil, fmt.Errorf("métrica de volume inválida: %s", q.Metric)
+	}
+
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("volume_ranking"))
+
+	args := []interface{}{q.Window, q.Compare}
+	var filters []string
+
+	if len(q.Tickers) > 0 {
+		args = append(args, q.Tickers)
+		filters = append(filters, fmt.Sprintf("AND da.codigo_instrumento = ANY($%d)", len(args)))
+	}
+	if q.Prefix != "" {
+		args = append(args, q.Prefix+"%")
+		filters = append(filters, fmt.Sprintf("AND da.codigo_instrumento LIKE $%d", len(args)))
+	}
+	if q.ExcludeFractional {
+		filters = append(filters, "AND da.codigo_instrumento !~ '^[A-Z0-9]{4}[0-9]{1,2}F$'")
+	}
+
+	args = append(args, q.PageSize, (q.Page-1)*q.PageSize)
+	limitArg, offsetArg := len(args)-1, len(args)
+
+	query := fmt.Sprintf(`
+        WITH sessions AS (
+            SELECT data_negocio, ROW_NUMBER() OVER (ORDER BY data_negocio DESC) as rn
+            FROM (SELECT DISTINCT data_negocio FROM daily_aggregations) d
+        ),
+        session_counts AS (
+            SELECT
+                COUNT(*) FILTER (WHERE rn <= $1) as window_sessions,
+                COUNT(*) FILTER (WHERE rn > $1 AND rn <= $1 + $2) as compare_sessions,
+                MIN(data_negocio) FILTER (WHERE rn <= $1) as window_start,
+                MAX(data_negocio) FILTER (WHERE rn <= $1) as window_end
+            FROM sessions
+        ),
+        current_window AS (
+            SELECT
+                da.codigo_instrumento,
+                SUM(da.%[1]s) as total_volume,
+                MAX(da.%[1]s) as max_volume
+            FROM daily_aggregations da
+            JOIN sessions s ON s.data_negocio = da.data_negocio
+            WHERE s.rn <= $1
+            %[2]s
+            GROUP BY da.codigo_instrumento
+        ),
+        compare_window AS (
+            SELECT
+                da.codigo_instrumento,
+                SUM(da.%[1]s) as total_volume
+            FROM daily_aggregations da
+            JOIN sessions s ON s.data_negocio = da.data_negocio
+            WHERE s.rn > $1 AND s.rn <= $1 + $2
+            %[2]s
+            GROUP BY da.codigo_instrumento
+        )
+        SELECT
+            c.codigo_instrumento,
+            c.total_volume,
+            c.total_volume / NULLIF(sc.window_sessions, 0) as avg_volume,
+            c.max_volume,
+            p.total_volume / NULLIF(sc.compare_sessions, 0) as compare_avg_volume,
+            sc.window_start,
+            sc.window_end,
+            COUNT(*) OVER () as total_count
+        FROM current_window c
+        CROSS JOIN session_counts sc
+        LEFT JOIN compare_window p ON p.codigo_instrumento = c.codigo_instrumento
+        ORDER BY c.total_volume DESC, c.codigo_instrumento
+        LIMIT $%[3]d OFFSET $%[4]d
+    `, column, strings.Join(filters, "\n            "), limitArg, offsetArg)
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("volume_ranking", "error").Inc()
+		return nil, fmt.Errorf("erro ao buscar ranking de volume: %w", err)
+	}
+	defer rows.Close()
+
+	result := &domain.VolumeRankingResult{
+		Metric:   q.Metric,
+		Window:   q.Window,
+		Compare:  q.Compare,
+		Data:     []domain.VolumeRanking{},
+		Page:     q.Page,
+		PageSize: q.PageSize,
+	}
+
+	offset := (q.Page - 1) * q.PageSize
+	for rows.Next() {
+		var item domain.VolumeRanking
+		var total, avg, maxVolume decimal.Decimal
+		var compareAvg *decimal.Decimal
+		var windowStart, windowEnd time.Time
+
+		err := rows.Scan(
+			&item.Ticker,
+			&total,
+			&avg,
+			&maxVolume,
+			&compareAvg,
+			&windowStart,
+			&windowEnd,
+			&result.TotalCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear ranking de volume: %w", err)
+		}
+
+		item.Position = offset + len(result.Data) + 1
+		item.TotalVolume = total.IntPart()
+		item.AvgDailyVolume = avg.IntPart()
+		item.MaxDailyVolume = maxVolume.IntPart()
+
+		if compareAvg != nil && compareAvg.IsPositive() {
+			item.VolumeChangePercent = avg.Div(*compareAvg).Sub(decimal.NewFromInt(1)).InexactFloat64() * 100
+		}
+
+		result.WindowStart = &windowStart
+		result.WindowEnd = &windowEnd
+		result.Data = append(result.Data, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar ranking de volume: %w", err)
+	}
+
+	result.HasMore = offset+len(result.Data) < result.TotalCount
+
+	metrics.DatabaseQueries.WithLabelValues("volume_ranking", "success").Inc()
+	return result, nil
+}
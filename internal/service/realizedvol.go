@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/internal/ingestion"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"github.com/jeovahfialho/b3-analyzer/pkg/stats"
+)
+
+var DefaultConeHorizons = []int{5, 10, 21, 63, 126}
+
+// GetRealizedVolatility devolve a série de volatilidade realizada do
+// período amostrando o último preço de cada janela de frequency. Os
+// pregões ainda não calculados nessa frequência são calculados a partir de
+// trades e gravados em realized_volatility antes da leitura.
+func (s *AnalysisService) GetRealizedVolatility(ctx context.Context, ticker string, startDate, endDate time.Time, frequency string, horizons []int) (*domain.RealizedVolResult, error) {
+	step, err := ParseCandleInterval(frequency)
+	if err != nil {
+		return nil, err
+	}
+	seconds := int64(step.Seconds())
+
+	if err := s.computeRealizedVolatility(ctx, ticker, startDate, endDate, seconds); err != nil {
+		return nil, err
+	}
+
+	series, err := s.queryRealizedVolatility(ctx, ticker, startDate, endDate, seconds)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(series) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return &domain.RealizedVolResult{
+		Ticker:    ticker,
+		Frequency: frequency,
+		StartDate: series[0].Date,
+		EndDate:   series[len(series)-1].Date,
+		Series:    series,
+		Cones:     realizedVolCones(series, horizons),
+	}, nil
+}
+
+func (s *AnalysisService) computeRealizedVolatility(ctx context.Context, ticker string, startDate, endDate time.Time, seconds int64) error {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("realized_vol_compute"))
+
+	query := `
+        INSERT INTO realized_volatility (
+            codigo_instrumento, data_negocio, frequency_seconds,
+            observations, realized_variance, realized_volatility
+        )
+        WITH pending AS (
+            SELECT da.data_negocio
+            FROM daily_aggregations da
+            WHERE da.codigo_instrumento = $1
+            AND da.data_negocio BETWEEN $2 AND $3
+            AND NOT EXISTS (
+                SELECT 1 FROM realized_volatility rv
+                WHERE rv.codigo_instrumento = da.codigo_instrumento
+                AND rv.frequency_seconds = $4
+                AND rv.data_negocio = da.data_negocio
+            )
+        ),
+        samples AS (
+            SELECT
+                data_negocio,
+                FLOOR(EXTRACT(EPOCH FROM hora_fechamento) / $4) as bucket,
+                (ARRAY_AGG(preco_negocio ORDER BY hora_fechamento DESC, id DESC))[1] as last_price
+            FROM trades
+            WHERE codigo_instrumento = $1
+            AND data_negocio IN (SELECT data_negocio FROM pending)
+            GROUP BY data_negocio, bucket
+        ),
+        returns AS (
+            SELECT
+                data_negocio,
+                LN(last_price / LAG(last_price) OVER (PARTITION BY data_negocio ORDER BY bucket))::float8 as r
+            FROM samples
+        )
+        SELECT
+            $1,
+            data_negocio,
+            $4,
+            COUNT(r),
+            COALESCE(SUM(r * r), 0),
+            SQRT(COALESCE(SUM(r * r), 0) * 252)
+        FROM returns
+        GROUP BY data_negocio
+        ON CONFLICT (codigo_instrumento, frequency_seconds, data_negocio) DO NOTHING
+    `
+
+	// O lock impede que o cálculo leia trades antes de uma carga em curso e
+	// grave depois que ela já limpou os pregões.
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, ingestion.DailyAggregationSharedLock); err != nil {
+		return fmt.Errorf("erro ao bloquear agregações: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, query, ticker, startDate, endDate, seconds); err != nil {
+		metrics.DatabaseQueries.WithLabelValues("realized_vol_compute", "error").Inc()
+		return fmt.Errorf("erro ao calcular volatilidade realizada: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro no commit: %w", err)
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("realized_vol_compute", "success").Inc()
+	return nil
+}
+
+func (s *AnalysisService) queryRealizedVolatility(ctx context.Context, ticker string, startDate, endDate time.Time, seconds int64) ([]domain.RealizedVolPoint, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("realized_vol"))
+
+	query := `
+        SELECT data_negocio, observations, realized_variance, realized_volatility
+        FROM realized_volatility
+        WHERE codigo_instrumento = $1
+        AND frequency_seconds = $4
+        AND data_negocio BETWEEN $2 AND $3
+        ORDER BY data_negocio
+    `
+
+	rows, err := s.pool.Query(ctx, query, ticker, startDate, endDate, seconds)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("realized_vol", "error").Inc()
+		return nil, fmt.Errorf("erro ao buscar volatilidade realizada: %w", err)
+	}
+	defer rows.Close()
+
+	series := []domain.RealizedVolPoint{}
+	for rows.Next() {
+		var point domain.RealizedVolPoint
+		if err := rows.Scan(&point.Date, &point.Observations, &point.RealizedVariance, &point.RealizedVolatility); err != nil {
+			return nil, fmt.Errorf("erro ao escanear volatilidade realizada: %w", err)
+		}
+		series = append(series, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar volatilidade realizada: %w", err)
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("realized_vol", "success").Inc()
+	return series, nil
+}
+
+// realizedVolCones anualiza a variância somada de cada janela de h pregões:
+// sqrt(252 / h * soma). Horizontes maiores que a série ficam de fora.
+func realizedVolCones(series []domain.RealizedVolPoint, horizons []int) []domain.RealizedVolCone {
+	cones := []domain.RealizedVolCone{}
+
+	for _, h := range horizons {
+		if h <= 0 || h > len(series) {
+			continue
+		}
+
+		vols := make([]float64, 0, len(series)-h+1)
+		sum := 0.0
+		for i, point := range series {
+			sum += point.RealizedVariance
+			if i >= h {
+				sum -= series[i-h].RealizedVariance
+			}
+			if i >= h-1 {
+				vols = append(vols, math.Sqrt(math.Max(sum, 0)*stats.TradingDaysPerYear/float64(h)))
+			}
+		}
+
+		cones = append(cones, domain.RealizedVolCone{
+			Horizon: h,
+			Windows: len(vols),
+			Min:     stats.Percentile(vols, 0),
+			P10:     stats.Percentile(vols, 10),
+			P25:     stats.Percentile(vols, 25),
+			P50:     stats.Percentile(vols, 50),
+			P75:     stats.Percentile(vols, 75),
+			P90:     stats.Percentile(vols, 90),
+			Max:     stats.Percentile(vols, 100),
+			Current: vols[len(vols)-1],
+		})
+	}
+
+	return cones
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+)
+
+func TestRealizedVolCones(t *testing.T) {
+	variances := []float64{0.0001, 0.0004, 0.0001, 0.0004}
+	series := make([]domain.RealizedVolPoint, len(variances))
+	for i, v := range variances {
+		series[i].RealizedVariance = v
+	}
+
+	cones := realizedVolCones(series, []int{1, 2, 10})
+	if len(cones) != 2 {
+		t.Fatalf("esperado 2 cones (horizonte 10 maior que a série), recebido %d", len(cones))
+	}
+
+	daily := cones[0]
+	if daily.Windows != 4 || math.Abs(daily.Min-math.Sqrt(0.0001*252)) > 1e-12 || math.Abs(daily.Max-math.Sqrt(0.0004*252)) > 1e-12 {
+		t.Errorf("cone de 1 pregão inesperado: %+v", daily)
+	}
+
+	// todas as janelas de 2 pregões somam 0.0005
+	twoDays := cones[1]
+	expected := math.Sqrt(0.0005 * 252 / 2)
+	if twoDays.Windows != 3 || math.Abs(twoDays.P50-expected) > 1e-12 || math.Abs(twoDays.Current-expected) > 1e-12 {
+		t.Errorf("cone de 2 pregões inesperado: %+v", twoDays)
+	}
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/internal/ingestion"
+	"github.com/jeovahfialho/b3-analyzer/pkg/logger"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+type ReconciliationService struct {
+	pool   *pgxpool.Pool
+	parser *ingestion.Parser
+}
+
+func NewReconciliationService(pool *pgxpool.Pool, parser *ingestion.Parser) *ReconciliationService {
+	return &ReconciliationService{
+		pool:   pool,
+		parser: parser,
+	}
+}
+
+type tickerTotals struct {
+	trades   int64
+	quantity int64
+	priceSum decimal.Decimal
+}
+
+// Reconcile relê o arquivo de origem e compara, por ticker, a quantidade de
+// negócios, a quantidade negociada e a soma dos preços com o que está
+// gravado em trades e em daily_aggregations para a data informada.
+func (s *ReconciliationService) Reconcile(ctx context.Context, filePath string, date time.Time) (*domain.ReconciliationReport, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("reconciliation"))
+
+	source, records, parseErrors, err := s.readSource(ctx, filePath, date)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.queryStoredTotals(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregated, err := s.queryAggregatedTotals(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+
+	tickers := make(map[string]struct{})
+	for _, totals := range []map[string]tickerTotals{source, stored, aggregated} {
+		for ticker := range totals {
+			tickers[ticker] = struct{}{}
+		}
+	}
+
+	report := &domain.ReconciliationReport{
+		Date:           date,
+		FilePath:       filePath,
+		SourceRecords:  records,
+		ParseErrors:    parseErrors,
+		TickersChecked: len(tickers),
+		Discrepancies:  []domain.TickerReconciliation{},
+		CheckedAt:      time.Now(),
+	}
+
+	for ticker := range tickers {
+		result := compareTotals(ticker, source[ticker], stored[ticker], aggregated[ticker])
+		if len(result.Issues) > 0 {
+			report.Discrepancies = append(report.Discrepancies, result)
+		}
+	}
+
+	sort.Slice(report.Discrepancies, func(i, j int) bool {
+		return report.Discrepancies[i].Ticker < report.Discrepancies[j].Ticker
+	})
+
+	report.Consistent = len(report.Discrepancies) == 0
+
+	logger.Info("reconciliação concluída",
+		zap.String("file", filePath),
+		zap.String("date", date.Format("2006-01-02")),
+		zap.Int("tickers", report.TickersChecked),
+		zap.Int("discrepancies", len(report.Discrepancies)))
+
+	return report, nil
+}
+
+func (s *ReconciliationService) readSource(ctx context.Context, filePath string, date time.Time) (map[string]tickerTotals, int, int, error) {
+	file, err := ingestion.OpenSourceFile(filePath)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("erro ao abrir arquivo: %w", err)
+	}
+	defer file.Close()
+
+	parseResult, err := s.parser.ParseFile(ctx, file)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("erro no parse: %w", err)
+	}
+
+	totals := make(map[string]tickerTotals)
+	records := 0
+
+	for _, trade := range parseResult.Trades {
+		if !trade.DataNegocio.Equal(date) {
+			continue
+		}
+		records++
+
+		t := totals[trade.CodigoInstrumento]
+		t.trades++
+		t.quantity += trade.QuantidadeNegociada
+		// preco_negocio é DECIMAL(10, 2), então o valor gravado é arredondado
+		t.priceSum = t.priceSum.Add(trade.PrecoNegocio.Round(2))
+		totals[trade.CodigoInstrumento] = t
+	}
+
+	return totals, records, len(parseResult.Errors), nil
+}
+
+func (s *ReconciliationService) queryStoredTotals(ctx context.Context, date time.Time) (map[string]tickerTotals, error) {
+	query := `
+        SELECT
+            codigo_instrumento,
+            COUNT(*),
+            SUM(quantidade_negociada),
+            SUM(preco_negocio)
+        FROM trades
+        WHERE data_negocio = $1
+        GROUP BY codigo_instrumento
+    `
+
+	rows, err := s.pool.Query(ctx, query, date)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("reconciliation", "error").Inc()
+		return nil, fmt.Errorf("erro ao buscar totais de trades: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make(map[string]tickerTotals)
+	for rows.Next() {
+		var ticker string
+		var t tickerTotals
+		if err := rows.Scan(&ticker, &t.trades, &t.quantity, &t.priceSum); err != nil {
+			return nil, fmt.Errorf("erro ao escanear totais: %w", err)
+		}
+		totals[ticker] = t
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar totais: %w", err)
+	}
+
+	return totals, nil
+}
+
+func (s *ReconciliationService) queryAggregatedTotals(ctx context.Context, date time.Time) (map[string]tickerTotals, error) {
+	query := `
+        SELECT
+            codigo_instrumento,
+            trade_count,
+            total_volume,
+            avg_price * trade_count
+        FROM daily_aggregations
+        WHERE data_negocio = $1
+    `
+
+	rows, err := s.pool.Query(ctx, query, date)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("reconciliation", "error").Inc()
+		return nil, fmt.Errorf("erro ao buscar agregações: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make(map[string]tickerTotals)
+	for rows.Next() {
+		var ticker string
+		var t tickerTotals
+		if err := rows.Scan(&ticker, &t.trades, &t.quantity, &t.priceSum); err != nil {
+			return nil, fmt.Errorf("erro ao escanear agregação: %w", err)
+		}
+		t.priceSum = t.priceSum.Round(2)
+		totals[ticker] = t
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar agregações: %w", err)
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("reconciliation", "success").Inc()
+	return totals, nil
+}
+
+func compareTotals(ticker string, source, stored, aggregated tickerTotals) domain.TickerReconciliation {
+	result := domain.TickerReconciliation{
+		Ticker:             ticker,
+		SourceTrades:       source.trades,
+		StoredTrades:       stored.trades,
+		AggregatedTrades:   aggregated.trades,
+		SourceQuantity:     source.quantity,
+		StoredQuantity:     stored.quantity,
+		AggregatedQuantity: aggregated.quantity,
+		SourcePriceSum:     source.priceSum,
+		StoredPriceSum:     stored.priceSum,
+		AggregatedPriceSum: aggregated.priceSum,
+		Issues:             []string{},
+	}
+
+	if source.trades != stored.trades {
+		result.Issues = append(result.Issues,
+			fmt.Sprintf("negócios: arquivo %d, trades %d", source.trades, stored.trades))
+	}
+	if source.quantity != stored.quantity {
+		result.Issues = append(result.Issues,
+			fmt.Sprintf("quantidade: arquivo %d, trades %d", source.quantity, stored.quantity))
+	}
+	if !source.priceSum.Equal(stored.priceSum) {
+		result.Issues = append(result.Issues,
+			fmt.Sprintf("soma de preços: arquivo %s, trades %s", source.priceSum, stored.priceSum))
+	}
+
+	if stored.trades != aggregated.trades {
+		result.Issues = append(result.Issues,
+			fmt.Sprintf("negócios: trades %d, daily_aggregations %d", stored.trades, aggregated.trades))
+	}
+	if stored.quantity != aggregated.quantity {
+		result.Issues = append(result.Issues,
+			fmt.Sprintf("quantidade: trades %d, daily_aggregations %d", stored.quantity, aggregated.quantity))
+	}
+	if !stored.priceSum.Round(2).Equal(aggregated.priceSum) {
+		result.Issues = append(result.Issues,
+			fmt.Sprintf("soma de preços: trades %s, daily_aggregations %s", stored.priceSum, aggregated.priceSum))
+	}
+
+	return result
+}
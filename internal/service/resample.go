@@ -0,0 +1,59 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+)
+
+func ValidFrequency(freq string) bool {
+	switch freq {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+		return true
+	}
+	return false
+}
+
+// periodKey identifica o período (dia, semana ISO ou mês) ao qual a data
+// pertence.
+func periodKey(date time.Time, freq string) string {
+	switch freq {
+	case FrequencyWeekly:
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case FrequencyMonthly:
+		return date.Format("2006-01")
+	}
+	return date.Format("2006-01-02")
+}
+
+type datedValue struct {
+	Date  time.Time
+	Value float64
+}
+
+// resampleLast mantém o último valor de cada período; a entrada deve estar
+// em ordem cronológica e a data retornada é a do último pregão do período.
+func resampleLast(values []datedValue, freq string) []datedValue {
+	if freq == FrequencyDaily {
+		return values
+	}
+
+	var out []datedValue
+	lastKey := ""
+	for _, v := range values {
+		key := periodKey(v.Date, freq)
+		if key == lastKey {
+			out[len(out)-1] = v
+			continue
+		}
+		out = append(out, v)
+		lastKey = key
+	}
+	return out
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/pkg/stats"
+)
+
+const (
+	ReturnTypeSimple = "simple"
+	ReturnTypeLog    = "log"
+)
+
+func ValidReturnType(returnType string) bool {
+	return returnType == ReturnTypeSimple || returnType == ReturnTypeLog
+}
+
+// periodsPerYear anualiza retornos e volatilidade na frequência da série.
+func periodsPerYear(freq string) float64 {
+	switch freq {
+	case FrequencyWeekly:
+		return 52
+	case FrequencyMonthly:
+		return 12
+	}
+	return stats.TradingDaysPerYear
+}
+
+// GetReturns monta a série de retornos dos fechamentos em [startDate,
+// endDate], reamostrada pelo último pregão de cada período de freq.
+func (s *AnalysisService) GetReturns(ctx context.Context, ticker string, startDate, endDate time.Time, returnType, freq string) (*domain.ReturnsResult, error) {
+	if !ValidReturnType(returnType) {
+		return nil, fmt.Errorf("type inválido: %s (use simple ou log)", returnType)
+	}
+	if !ValidFrequency(freq) {
+		return nil, fmt.Errorf("freq inválida: %s (use daily, weekly ou monthly)", freq)
+	}
+
+	closes, err := s.queryCloses(ctx, []string{ticker}, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	series := resampleLast(closes[ticker], freq)
+	if len(series) < 2 {
+		return nil, ErrNotFound
+	}
+
+	points, summary := buildReturns(series, returnType, freq)
+	return &domain.ReturnsResult{
+		Ticker:    ticker,
+		Type:      returnType,
+		Frequency: freq,
+		StartDate: series[0].Date,
+		EndDate:   series[len(series)-1].Date,
+		Summary:   summary,
+		Series:    points,
+	}, nil
+}
+
+// buildReturns calcula a série e o resumo a partir de fechamentos em ordem
+// cronológica, com ao menos dois pontos.
+func buildReturns(closes []datedValue, returnType, freq string) ([]domain.ReturnPoint, domain.ReturnsSummary) {
+	prices := make([]float64, len(closes))
+	for i, c := range closes {
+		prices[i] = c.Value
+	}
+
+	returns := stats.SimpleReturns(prices)
+	if returnType == ReturnTypeLog {
+		returns = stats.LogReturns(prices)
+	}
+
+	cumulative := func(price float64) float64 {
+		if returnType == ReturnTypeLog {
+			return math.Log(price / prices[0])
+		}
+		return price/prices[0] - 1
+	}
+
+	points := make([]domain.ReturnPoint, len(closes))
+	var longest, current *domain.DrawdownPeriod
+	peak, peakIdx := prices[0], 0
+
+	for i, c := range closes {
+		if c.Value >= peak {
+			if current != nil {
+				current.End, current.Periods, current.Recovered = c.Date, i-peakIdx, true
+				longest = longerDrawdown(longest, current)
+				current = nil
+			}
+			peak, peakIdx = c.Value, i
+		} else if current == nil {
+			current = &domain.DrawdownPeriod{Start: closes[peakIdx].Date}
+		}
+
+		points[i] = domain.ReturnPoint{
+			Date:             c.Date,
+			Close:            c.Value,
+			CumulativeReturn: cumulative(c.Value),
+			RunningMax:       peak,
+			Drawdown:         (peak - c.Value) / peak,
+		}
+		if i > 0 {
+			r := returns[i-1]
+			points[i].Return = &r
+		}
+	}
+
+	last := len(closes) - 1
+	if current != nil {
+		current.End, current.Periods = closes[last].Date, last-peakIdx
+		longest = longerDrawdown(longest, current)
+	}
+	if longest != nil {
+		longest.Days = int(longest.End.Sub(longest.Start).Hours() / 24)
+	}
+
+	ppy := periodsPerYear(freq)
+	summary := domain.ReturnsSummary{
+		TotalReturn:     cumulative(prices[last]),
+		LongestDrawdown: longest,
+	}
+
+	if returnType == ReturnTypeLog {
+		summary.AnnualizedReturn = summary.TotalReturn * ppy / float64(len(returns))
+	} else {
+		summary.AnnualizedReturn = math.Pow(prices[last]/prices[0], ppy/float64(len(returns))) - 1
+	}
+
+	if vol := stats.StdDev(returns); !math.IsNaN(vol) {
+		summary.AnnualizedVolatility = vol * math.Sqrt(ppy)
+	}
+
+	maxDD, peakAt, troughAt := stats.MaxDrawdown(prices)
+	if maxDD > 0 {
+		summary.MaxDrawdown = maxDD
+		summary.MaxDrawdownPeak = &closes[peakAt].Date
+		summary.MaxDrawdownTrough = &closes[troughAt].Date
+	}
+
+	return points, summary
+}
+
+func longerDrawdown(a, b *domain.DrawdownPeriod) *domain.DrawdownPeriod {
+	if a == nil || b.Periods > a.Periods {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBuildReturns(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	closes := []datedValue{
+		{day(1), 100}, {day(2), 110}, {day(3), 99}, {day(4), 105}, {day(5), 121}, {day(8), 115},
+	}
+
+	points, summary := buildReturns(closes, ReturnTypeSimple, FrequencyDaily)
+	if len(points) != len(closes) || points[0].Return != nil {
+		t.Fatalf("o primeiro ponto deveria ser a base: %+v", points[0])
+	}
+	if math.Abs(*points[1].Return-0.10) > 1e-9 || math.Abs(points[4].CumulativeReturn-0.21) > 1e-9 {
+		t.Errorf("retornos incorretos: %+v", points)
+	}
+	if points[2].RunningMax != 110 || math.Abs(points[2].Drawdown-0.10) > 1e-9 {
+		t.Errorf("drawdown incorreto: %+v", points[2])
+	}
+
+	if math.Abs(summary.TotalReturn-0.15) > 1e-9 {
+		t.Errorf("retorno total esperado 0.15, recebido %v", summary.TotalReturn)
+	}
+	if math.Abs(summary.MaxDrawdown-0.10) > 1e-9 || !summary.MaxDrawdownPeak.Equal(day(2)) || !summary.MaxDrawdownTrough.Equal(day(3)) {
+		t.Errorf("drawdown máximo incorreto: %+v", summary)
+	}
+
+	// 02/01 a 05/01 (recuperado, 3 períodos) contra 05/01 até o fim (1 período).
+	longest := summary.LongestDrawdown
+	if longest == nil || !longest.Start.Equal(day(2)) || !longest.End.Equal(day(5)) || longest.Periods != 3 || longest.Days != 3 || !longest.Recovered {
+		t.Errorf("maior drawdown incorreto: %+v", longest)
+	}
+
+	_, logSummary := buildReturns(closes, ReturnTypeLog, FrequencyDaily)
+	if math.Abs(logSummary.TotalReturn-math.Log(1.15)) > 1e-9 {
+		t.Errorf("retorno total log incorreto: %v", logSummary.TotalReturn)
+	}
+	if math.Abs(logSummary.AnnualizedReturn-math.Log(1.15)*252/5) > 1e-9 {
+		t.Errorf("retorno anualizado log incorreto: %v", logSummary.AnnualizedReturn)
+	}
+}
@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	screenerDefaultDays  = 20
+	screenerDefaultLimit = 50
+	screenerMaxLimit     = 500
+
+	// sentinelas para que tickers sem a métrica fiquem sempre no fim
+	screenerNullsLow  = -1e18
+	screenerNullsHigh = 1e18
+)
+
+var screenerSortColumns = map[string]string{
+	"ticker":        "codigo_instrumento",
+	"last_close":    "last_close",
+	"max_price":     "max_price",
+	"avg_volume":    "avg_volume",
+	"max_volume":    "max_volume",
+	"volatility":    "volatility",
+	"return":        "return_percent",
+	"range_percent": "range_percent",
+}
+
+type screenerCursor struct {
+	SortBy    string  `json:"s"`
+	SortOrder string  `json:"o"`
+	Value     float64 `json:"v"`
+	Ticker    string  `json:"t"`
+	Page      int     `json:"p"`
+}
+
+func encodeScreenerCursor(c screenerCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeScreenerCursor(raw string) (screenerCursor, error) {
+	var c screenerCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return c, fmt.Errorf("cursor inválido")
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.Ticker == "" || c.Page < 1 {
+		return c, fmt.Errorf("cursor inválido")
+	}
+
+	return c, nil
+}
+
+// NormalizeScreenerFilter valida o filtro e preenche os padrões: últimos 20
+// pregões, ordenação por volume médio decrescente e 50 resultados.
+func NormalizeScreenerFilter(f *domain.AggregationFilter) error {
+	if f.StartDate != nil && f.EndDate != nil && f.EndDate.Before(*f.StartDate) {
+		return fmt.Errorf("end_date deve ser posterior a start_date")
+	}
+	if f.Days < 0 {
+		return fmt.Errorf("days não pode ser negativo")
+	}
+	if f.Days == 0 && f.StartDate == nil && f.EndDate == nil {
+		f.Days = screenerDefaultDays
+	}
+
+	if f.SortBy == "" {
+		f.SortBy = "avg_volume"
+	}
+	if _, ok := screenerSortColumns[f.SortBy]; !ok {
+		return fmt.Errorf("sort_by inválido: %s", f.SortBy)
+	}
+
+	f.SortOrder = strings.ToLower(f.SortOrder)
+	if f.SortOrder == "" {
+		f.SortOrder = "desc"
+		if f.SortBy == "ticker" {
+			f.SortOrder = "asc"
+		}
+	}
+	if f.SortOrder != "asc" && f.SortOrder != "desc" {
+		return fmt.Errorf("sort_order inválido: %s (use asc ou desc)", f.SortOrder)
+	}
+
+	if f.Limit == 0 {
+		f.Limit = screenerDefaultLimit
+	}
+	if f.Limit < 0 || f.Limit > screenerMaxLimit {
+		return fmt.Errorf("limit deve estar entre 1 e %d", screenerMaxLimit)
+	}
+
+	if f.Cursor != "" {
+		cursor, err := decodeScreenerCursor(f.Cursor)
+		if err != nil {
+			return err
+		}
+		if cursor.SortBy != f.SortBy || cursor.SortOrder != f.SortOrder {
+			return fmt.Errorf("cursor não corresponde à ordenação solicitada")
+		}
+	}
+
+	for i, ticker := range f.Tickers {
+		f.Tickers[i] = strings.ToUpper(strings.TrimSpace(ticker))
+	}
+
+	return nil
+}
+
+// Screen filtra o universo de tickers pelas métricas calculadas no período
+// do filtro. A volatilidade é o desvio padrão anualizado dos log-retornos
+// diários e o retorno compara o primeiro e o último fechamento, ambos em
+// percentual. A paginação é por cursor sobre (chave de ordenação, ticker);
+// o filtro deve estar normalizado por NormalizeScreenerFilter.
+func (s *AnalysisService) Screen(ctx context.Context, f domain.AggregationFilter) (*domain.AggregationResult, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("screener"))
+
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var sessionFilters []string
+	if f.StartDate != nil {
+		sessionFilters = append(sessionFilters, "data_negocio >= "+arg(*f.StartDate))
+	}
+	if f.EndDate != nil {
+		sessionFilters = append(sessionFilters, "data_negocio <= "+arg(*f.EndDate))
+	}
+	sessionWhere := ""
+	if len(sessionFilters) > 0 {
+		sessionWhere = "WHERE " + strings.Join(sessionFilters, " AND ")
+	}
+	sessionLimit := ""
+	if f.Days > 0 {
+		sessionLimit = "LIMIT " + arg(f.Days)
+	}
+
+	tickerFilter := ""
+	if len(f.Tickers) > 0 {
+		tickerFilter = "AND da.codigo_instrumento = ANY(" + arg(f.Tickers) + ")"
+	}
+
+	var filters []string
+	addRange := func(column string, min, max interface{}) {
+		if min != nil {
+			filters = append(filters, fmt.Sprintf("AND %s >= %s", column, arg(min)))
+		}
+		if max != nil {
+			filters = append(filters, fmt.Sprintf("AND %s <= %s", column, arg(max)))
+		}
+	}
+	addRange("last_close", decimalArg(f.MinPrice), decimalArg(f.MaxPrice))
+	addRange("avg_volume", int64Arg(f.MinVolume), int64Arg(f.MaxVolume))
+	addRange("volatility", float64Arg(f.MinVolatility), float64Arg(f.MaxVolatility))
+	addRange("return_percent", float64Arg(f.MinReturn), float64Arg(f.MaxReturn))
+	addRange("range_percent", float64Arg(f.MinRangePercent), float64Arg(f.MaxRangePercent))
+
+	sortKey, direction := screenerSortKey(f.SortBy, f.SortOrder)
+
+	page := 1
+	cursorFilter := ""
+	if f.Cursor != "" {
+		cursor, err := decodeScreenerCursor(f.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		page = cursor.Page + 1
+
+		comparison := ">"
+		if direction == "DESC" {
+			comparison = "<"
+		}
+		if f.SortBy == "ticker" {
+			cursorFilter = fmt.Sprintf("WHERE codigo_instrumento %s %s", comparison, arg(cursor.Ticker))
+		} else {
+			value, ticker := arg(cursor.Value), arg(cursor.Ticker)
+			cursorFilter = fmt.Sprintf("WHERE sort_key %[1]s %[2]s OR (sort_key = %[2]s AND codigo_instrumento > %[3]s)",
+				comparison, value, ticker)
+		}
+	}
+
+	limit := arg(f.Limit + 1)
+
+	query := fmt.Sprintf(`
+        WITH sessions AS (
+            SELECT data_negocio
+            FROM (SELECT DISTINCT data_negocio FROM daily_aggregations %[1]s) d
+            ORDER BY data_negocio DESC
+            %[2]s
+        ),
+        bars AS (
+            SELECT
+                da.codigo_instrumento,
+                da.data_negocio,
+                da.close_price,
+                da.max_price,
+                da.min_price,
+                da.total_volume,
+                CASE WHEN da.close_price > 0 AND LAG(da.close_price) OVER w > 0
+                    THEN LN(da.close_price / LAG(da.close_price) OVER w)
+                END as log_return
+            FROM daily_aggregations da
+            WHERE da.data_negocio IN (SELECT data_negocio FROM sessions)
+            %[3]s
+            WINDOW w AS (PARTITION BY da.codigo_instrumento ORDER BY da.data_negocio)
+        ),
+        computed AS (
+            SELECT
+                codigo_instrumento,
+                MAX(max_price) as max_price,
+                MIN(min_price) as min_price,
+                (ARRAY_AGG(close_price ORDER BY data_negocio))[1] as first_close,
+                (ARRAY_AGG(close_price ORDER BY data_negocio DESC))[1] as last_close,
+                MAX(total_volume) as max_volume,
+                AVG(total_volume) as avg_volume,
+                (STDDEV_SAMP(log_return) * SQRT(252) * 100)::float8 as volatility,
+                COUNT(*) as sessions
+            FROM bars
+            GROUP BY codigo_instrumento
+        ),
+        metrics AS (
+            SELECT
+                *,
+                ((last_close / NULLIF(first_close, 0) - 1) * 100)::float8 as return_percent,
+                ((max_price - min_price) / NULLIF(min_price, 0) * 100)::float8 as range_percent
+            FROM computed
+        ),
+        filtered AS (
+            SELECT *, %[4]s as sort_key
+            FROM metrics
+            WHERE TRUE
+            %[5]s
+        )
+        SELECT
+            codigo_instrumento,
+            max_price,
+            min_price,
+            last_close,
+            max_volume,
+            avg_volume,
+            volatility,
+            return_percent,
+            range_percent,
+            sessions,
+            sort_key,
+            (SELECT COUNT(*) FROM filtered) as total_count
+        FROM filtered
+        %[6]s
+        ORDER BY sort_key %[7]s, codigo_instrumento
+        LIMIT %[8]s
+    `, sessionWhere, sessionLimit, tickerFilter, sortKey,
+		strings.Join(filters, "\n            "), cursorFilter, direction, limit)
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("screener", "error").Inc()
+		return nil, fmt.Errorf("erro ao executar screener: %w", err)
+	}
+	defer rows.Close()
+
+	result := &domain.AggregationResult{
+		Data:     []domain.Aggregation{},
+		Page:     page,
+		PageSize: f.Limit,
+	}
+
+	var lastKey float64
+	for rows.Next() {
+		var item domain.Aggregation
+		var maxPrice, minPrice, lastClose, maxVolume, avgVolume *decimal.Decimal
+		var sortKey interface{}
+
+		err := rows.Scan(
+			&item.Ticker,
+			&maxPrice,
+			&minPrice,
+			&lastClose,
+			&maxVolume,
+			&avgVolume,
+			&item.Volatility,
+			&item.ReturnPercent,
+			&item.PriceRangePercent,
+			&item.Sessions,
+			&sortKey,
+			&result.TotalCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear screener: %w", err)
+		}
+
+		if len(result.Data) == f.Limit {
+			result.HasMore = true
+			break
+		}
+
+		if maxPrice != nil {
+			item.MaxRangeValue = *maxPrice
+		}
+		if maxVolume != nil {
+			item.MaxDailyVolume = maxVolume.IntPart()
+		}
+		item.MinPrice = minPrice
+		item.LastClose = lastClose
+		if avgVolume != nil {
+			avg := avgVolume.IntPart()
+			item.AvgDailyVolume = &avg
+		}
+		if key, ok := sortKey.(float64); ok {
+			lastKey = key
+		}
+
+		result.Data = append(result.Data, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar screener: %w", err)
+	}
+
+	if result.HasMore {
+		result.NextCursor = encodeScreenerCursor(screenerCursor{
+			SortBy:    f.SortBy,
+			SortOrder: f.SortOrder,
+			Value:     lastKey,
+			Ticker:    result.Data[len(result.Data)-1].Ticker,
+			Page:      page,
+		})
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("screener", "success").Inc()
+	return result, nil
+}
+
+// screenerSortKey devolve a expressão de ordenação e a direção. Exceto para
+// ticker, a chave é float8 com sentinela para valores nulos, o que permite
+// comparar com o valor guardado no cursor.
+func screenerSortKey(sortBy, order string) (string, string) {
+	direction := strings.ToUpper(order)
+	column := screenerSortColumns[sortBy]
+	if sortBy == "ticker" {
+		return column, direction
+	}
+
+	sentinel := screenerNullsHigh
+	if direction == "DESC" {
+		sentinel = screenerNullsLow
+	}
+	return fmt.Sprintf("COALESCE(%s::float8, %g)", column, sentinel), direction
+}
+
+func decimalArg(v *decimal.Decimal) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func int64Arg(v *int64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func float64Arg(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
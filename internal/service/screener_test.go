@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+)
+
+func TestScreenerCursor(t *testing.T) {
+	cursor := screenerCursor{SortBy: "volatility", SortOrder: "desc", Value: 31.0625, Ticker: "PETR4", Page: 2}
+
+	decoded, err := decodeScreenerCursor(encodeScreenerCursor(cursor))
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if decoded != cursor {
+		t.Errorf("esperado %+v, recebido %+v", cursor, decoded)
+	}
+
+	if _, err := decodeScreenerCursor("não-é-base64"); err == nil {
+		t.Error("esperado erro para cursor inválido")
+	}
+}
+
+func TestNormalizeScreenerFilter(t *testing.T) {
+	f := domain.AggregationFilter{Tickers: []string{" petr4 "}}
+	if err := NormalizeScreenerFilter(&f); err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if f.Days != screenerDefaultDays || f.SortBy != "avg_volume" || f.SortOrder != "desc" || f.Limit != screenerDefaultLimit {
+		t.Errorf("padrões não aplicados: %+v", f)
+	}
+	if f.Tickers[0] != "PETR4" {
+		t.Errorf("esperado PETR4, recebido %q", f.Tickers[0])
+	}
+
+	// cursor gerado para outra ordenação
+	f.Cursor = encodeScreenerCursor(screenerCursor{SortBy: "return", SortOrder: "desc", Ticker: "VALE3", Page: 1})
+	if err := NormalizeScreenerFilter(&f); err == nil {
+		t.Error("esperado erro para cursor de outra ordenação")
+	}
+
+	if err := NormalizeScreenerFilter(&domain.AggregationFilter{SortBy: "pe_ratio"}); err == nil {
+		t.Error("esperado erro para sort_by inválido")
+	}
+}
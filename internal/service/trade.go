@@ -35,8 +35,13 @@ func (s *TradeService) GetTickerHistory(ctx context.Context, ticker string, star
             avg_price,
             total_volume,
             trade_count,
-            price_stddev
-        FROM daily_aggregations
+            price_stddev,
+            open_price,
+            close_price,
+            vwap,
+            financial_volume,
+            block_volume
+        FROM ` + dailySource(ticker) + `
         WHERE codigo_instrumento = $1
     `
 
@@ -72,7 +77,7 @@ func (s *TradeService) GetTickerHistory(ctx context.Context, ticker string, star
 	var history []domain.DailyAggregation
 	for rows.Next() {
 		var agg domain.DailyAggregation
-		var priceStdDev *decimal.Decimal
+		var priceStdDev, vwap *decimal.Decimal
 
 		err := rows.Scan(
 			&agg.CodigoInstrumento,
@@ -83,6 +88,11 @@ func (s *TradeService) GetTickerHistory(ctx context.Context, ticker string, star
 			&agg.TotalVolume,
 			&agg.TradeCount,
 			&priceStdDev,
+			&agg.OpenPrice,
+			&agg.ClosePrice,
+			&vwap,
+			&agg.FinancialVolume,
+			&agg.BlockVolume,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("erro ao escanear linha: %w", err)
@@ -91,6 +101,12 @@ func (s *TradeService) GetTickerHistory(ctx context.Context, ticker string, star
 		if priceStdDev != nil {
 			agg.PriceStdDev = *priceStdDev
 		}
+		if vwap != nil {
+			agg.VWAP = *vwap
+		}
+		if agg.TotalVolume > 0 {
+			agg.BlockVolumePercent = float64(agg.BlockVolume) / float64(agg.TotalVolume) * 100
+		}
 
 		history = append(history, agg)
 	}
@@ -202,33 +218,76 @@ func (s *TradeService) GetTradesByDate(ctx context.Context, ticker string, date
 }
 
 func (s *TradeService) GetTickerStats(ctx context.Context, ticker string, days int) (*domain.TickerStats, error) {
+	dateFilter := fmt.Sprintf("AND data_negocio >= CURRENT_DATE - INTERVAL '%d days'", days)
+
+	stats, err := s.queryTickerStats(ctx, ticker, dateFilter)
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar estatísticas: %w", err)
+	}
+
+	stats.Period = fmt.Sprintf("%d days", days)
+	return stats, nil
+}
+
+// GetTickerStatsRange calcula as mesmas estatísticas de GetTickerStats no
+// período [startDate, endDate].
+func (s *TradeService) GetTickerStatsRange(ctx context.Context, ticker string, startDate, endDate time.Time) (*domain.TickerStats, error) {
+	stats, err := s.queryTickerStats(ctx, ticker, "AND data_negocio BETWEEN $2 AND $3", startDate, endDate)
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar estatísticas: %w", err)
+	}
+
+	stats.Period = fmt.Sprintf("%s a %s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	return stats, nil
+}
+
+// queryTickerStats devolve pgx.ErrNoRows quando o ticker não negociou no
+// período.
+func (s *TradeService) queryTickerStats(ctx context.Context, ticker, dateFilter string, args ...interface{}) (*domain.TickerStats, error) {
 	timer := metrics.NewTimer()
 	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("ticker_stats"))
 
 	query := `
-        WITH stats AS (
+        WITH daily AS (
+            SELECT
+                *,
+                LN(close_price / LAG(close_price) OVER (ORDER BY data_negocio)) as log_return
+            FROM %s
+            WHERE codigo_instrumento = $1
+            %s
+        ),
+        stats AS (
             SELECT 
                 COUNT(DISTINCT data_negocio) as days_traded,
                 SUM(total_volume) as total_volume,
                 SUM(trade_count) as total_trades,
-                AVG(total_volume) as avg_daily_volume,
+                AVG(total_volume)::bigint as avg_daily_volume,
                 AVG(avg_price) as avg_price,
                 MIN(min_price) as min_price,
                 MAX(max_price) as max_price,
-                STDDEV(avg_price) as price_stddev
-            FROM daily_aggregations
-            WHERE codigo_instrumento = $1
-            AND data_negocio >= CURRENT_DATE - INTERVAL '%d days'
+                STDDEV_SAMP(log_return) * SQRT(252) as volatility,
+                (ARRAY_AGG(open_price ORDER BY data_negocio))[1] as open_price,
+                (ARRAY_AGG(close_price ORDER BY data_negocio DESC))[1] as close_price,
+                SUM(financial_volume) as financial_volume,
+                %s as vwap
+            FROM daily
         )
-        SELECT * FROM stats
+        SELECT * FROM stats WHERE days_traded > 0
     `
 
-	query = fmt.Sprintf(query, days)
+	query = fmt.Sprintf(query, dailySource(ticker), dateFilter, periodVWAP(ticker))
 
 	var stats domain.TickerStats
-	var priceStdDev float64
+	var volatility *float64
+	var vwap *decimal.Decimal
 
-	err := s.pool.QueryRow(ctx, query, ticker).Scan(
+	err := s.pool.QueryRow(ctx, query, append([]interface{}{ticker}, args...)...).Scan(
 		&stats.DaysTraded,
 		&stats.TotalVolume,
 		&stats.TotalTrades,
@@ -236,33 +295,64 @@ func (s *TradeService) GetTickerStats(ctx context.Context, ticker string, days i
 		&stats.AvgPrice,
 		&stats.MinPrice,
 		&stats.MaxPrice,
-		&priceStdDev,
+		&volatility,
+		&stats.OpenPrice,
+		&stats.ClosePrice,
+		&stats.FinancialVolume,
+		&vwap,
 	)
-
-	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("nenhum dado encontrado para ticker %s", ticker)
-	}
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar estatísticas: %w", err)
+		if err != pgx.ErrNoRows {
+			metrics.DatabaseQueries.WithLabelValues("ticker_stats", "error").Inc()
+		}
+		return nil, err
 	}
 
 	stats.Ticker = ticker
-	stats.Period = fmt.Sprintf("%d days", days)
 	stats.PriceRange = stats.MaxPrice.Sub(stats.MinPrice)
-	stats.Volatility = priceStdDev * 15.87
+	if vwap != nil {
+		stats.VWAP = *vwap
+	}
+	if volatility != nil {
+		stats.Volatility = *volatility
+	}
 	stats.LastUpdate = time.Now()
 
 	metrics.DatabaseQueries.WithLabelValues("ticker_stats", "success").Inc()
 	return &stats, nil
 }
 
-func (s *TradeService) GetMarketOverview(ctx context.Context, date time.Time) (*domain.MarketOverview, error) {
+type MarketOverviewOptions struct {
+	MinFinancialVolume decimal.Decimal
+	MinTrades          int
+	Limit              int
+}
+
+// LatestSession retorna o último pregão carregado.
+func (s *TradeService) LatestSession(ctx context.Context) (time.Time, error) {
+	var latest *time.Time
+	if err := s.pool.QueryRow(ctx, "SELECT MAX(data_negocio) FROM daily_aggregations").Scan(&latest); err != nil {
+		return time.Time{}, fmt.Errorf("erro ao buscar último pregão: %w", err)
+	}
+	if latest == nil {
+		return time.Time{}, ErrNotFound
+	}
+	return *latest, nil
+}
+
+// GetMarketOverview compara o fechamento de cada ticker no pregão com o
+// fechamento do pregão anterior carregado (não o dia corrido anterior), de
+// modo que segundas-feiras e pregões pós-feriado também tenham variação.
+// Tickers abaixo do filtro de liquidez ficam fora de altas e baixas.
+func (s *TradeService) GetMarketOverview(ctx context.Context, date time.Time, opts MarketOverviewOptions) (*domain.MarketOverview, error) {
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("market_overview"))
 
 	statsQuery := `
         SELECT 
             COUNT(DISTINCT codigo_instrumento) as active_tickers,
-            SUM(total_volume) as total_volume,
-            SUM(trade_count) as total_trades
+            COALESCE(SUM(total_volume), 0) as total_volume,
+            COALESCE(SUM(trade_count), 0) as total_trades
         FROM daily_aggregations
         WHERE data_negocio = $1
     `
@@ -276,76 +366,205 @@ func (s *TradeService) GetMarketOverview(ctx context.Context, date time.Time) (*
 		&overview.TotalTrades,
 	)
 	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("market_overview", "error").Inc()
 		return nil, fmt.Errorf("erro ao buscar estatísticas gerais: %w", err)
 	}
 
+	if overview.ActiveTickers == 0 {
+		return nil, ErrNotFound
+	}
+
 	moversQuery := `
-        WITH price_changes AS (
+        WITH previous_session AS (
+            SELECT MAX(data_negocio) as data_negocio
+            FROM daily_aggregations
+            WHERE data_negocio < $1
+        ),
+        week_sessions AS (
+            SELECT DISTINCT data_negocio
+            FROM daily_aggregations
+            WHERE data_negocio <= $1
+            ORDER BY data_negocio DESC
+            LIMIT 5
+        ),
+        week_range AS (
+            SELECT
+                codigo_instrumento,
+                MAX(max_price) as week_high,
+                MIN(min_price) as week_low
+            FROM daily_aggregations
+            WHERE data_negocio IN (SELECT data_negocio FROM week_sessions)
+            GROUP BY codigo_instrumento
+        ),
+        price_changes AS (
             SELECT 
                 t1.codigo_instrumento,
-                t1.avg_price as current_price,
-                t2.avg_price as previous_price,
+                t1.close_price as current_price,
+                t2.close_price as previous_price,
                 t1.max_price as day_high,
                 t1.min_price as day_low,
-                ((t1.avg_price - t2.avg_price) / t2.avg_price * 100) as change_percent
+                w.week_high,
+                w.week_low,
+                ((t1.close_price - t2.close_price) / t2.close_price * 100) as change_percent
             FROM daily_aggregations t1
             JOIN daily_aggregations t2 
                 ON t1.codigo_instrumento = t2.codigo_instrumento
-                AND t2.data_negocio = t1.data_negocio - INTERVAL '1 day'
+                AND t2.data_negocio = (SELECT data_negocio FROM previous_session)
+            JOIN week_range w
+                ON w.codigo_instrumento = t1.codigo_instrumento
             WHERE t1.data_negocio = $1
+            AND t1.financial_volume >= $2
+            AND t1.trade_count >= $3
+            AND t2.close_price > 0
         )
         SELECT * FROM (
-            SELECT * FROM price_changes 
+            SELECT 'gainer', * FROM price_changes 
+            WHERE change_percent > 0
             ORDER BY change_percent DESC 
-            LIMIT 5
+            LIMIT $4
         ) gainers
         UNION ALL
         SELECT * FROM (
-            SELECT * FROM price_changes 
+            SELECT 'loser', * FROM price_changes 
+            WHERE change_percent < 0
             ORDER BY change_percent ASC 
-            LIMIT 5
+            LIMIT $4
         ) losers
     `
 
-	rows, err := s.pool.Query(ctx, moversQuery, date)
+	rows, err := s.pool.Query(ctx, moversQuery, date, opts.MinFinancialVolume, opts.MinTrades, opts.Limit)
 	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("market_overview", "error").Inc()
 		return nil, fmt.Errorf("erro ao buscar movers: %w", err)
 	}
 	defer rows.Close()
 
-	var gainers, losers []domain.PriceMovement
-	count := 0
+	gainers := []domain.PriceMovement{}
+	losers := []domain.PriceMovement{}
 
 	for rows.Next() {
+		var kind string
 		var movement domain.PriceMovement
 		var changePercent float64
 
 		err := rows.Scan(
+			&kind,
 			&movement.Ticker,
 			&movement.CurrentPrice,
 			&movement.PreviousPrice,
 			&movement.DayHigh,
 			&movement.DayLow,
+			&movement.WeekHigh,
+			&movement.WeekLow,
 			&changePercent,
 		)
 		if err != nil {
-			continue
+			return nil, fmt.Errorf("erro ao escanear mover: %w", err)
 		}
 
 		movement.Change = movement.CurrentPrice.Sub(movement.PreviousPrice)
 		movement.ChangePercent = changePercent
 
-		if count < 5 {
+		if kind == "gainer" {
 			gainers = append(gainers, movement)
 		} else {
 			losers = append(losers, movement)
 		}
-		count++
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar movers: %w", err)
+	}
+
+	mostTraded, err := s.getMostTraded(ctx, date, opts)
+	if err != nil {
+		return nil, err
 	}
 
 	overview.TopGainers = gainers
 	overview.TopLosers = losers
+	overview.MostTraded = mostTraded
 	overview.UpdatedAt = time.Now()
 
+	metrics.DatabaseQueries.WithLabelValues("market_overview", "success").Inc()
 	return &overview, nil
 }
+
+// getMostTraded ranqueia os tickers pelo volume do pregão; médias e máximos
+// consideram os últimos 20 pregões e a variação é contra o pregão anterior.
+func (s *TradeService) getMostTraded(ctx context.Context, date time.Time, opts MarketOverviewOptions) ([]domain.VolumeRanking, error) {
+	query := `
+        WITH recent_sessions AS (
+            SELECT DISTINCT data_negocio
+            FROM daily_aggregations
+            WHERE data_negocio <= $1
+            ORDER BY data_negocio DESC
+            LIMIT 20
+        ),
+        previous_session AS (
+            SELECT MAX(data_negocio) as data_negocio
+            FROM daily_aggregations
+            WHERE data_negocio < $1
+        ),
+        history AS (
+            SELECT
+                codigo_instrumento,
+                AVG(total_volume)::bigint as avg_volume,
+                MAX(total_volume) as max_volume
+            FROM daily_aggregations
+            WHERE data_negocio IN (SELECT data_negocio FROM recent_sessions)
+            GROUP BY codigo_instrumento
+        )
+        SELECT
+            t1.codigo_instrumento,
+            t1.total_volume,
+            h.avg_volume,
+            h.max_volume,
+            t2.total_volume
+        FROM daily_aggregations t1
+        JOIN history h ON h.codigo_instrumento = t1.codigo_instrumento
+        LEFT JOIN daily_aggregations t2
+            ON t2.codigo_instrumento = t1.codigo_instrumento
+            AND t2.data_negocio = (SELECT data_negocio FROM previous_session)
+        WHERE t1.data_negocio = $1
+        AND t1.trade_count >= $2
+        ORDER BY t1.total_volume DESC
+        LIMIT $3
+    `
+
+	rows, err := s.pool.Query(ctx, query, date, opts.MinTrades, opts.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar mais negociados: %w", err)
+	}
+	defer rows.Close()
+
+	ranking := []domain.VolumeRanking{}
+	for rows.Next() {
+		var item domain.VolumeRanking
+		var previousVolume *int64
+
+		err := rows.Scan(
+			&item.Ticker,
+			&item.TotalVolume,
+			&item.AvgDailyVolume,
+			&item.MaxDailyVolume,
+			&previousVolume,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear mais negociado: %w", err)
+		}
+
+		item.Position = len(ranking) + 1
+		if previousVolume != nil && *previousVolume > 0 {
+			item.VolumeChangePercent = (float64(item.TotalVolume)/float64(*previousVolume) - 1) * 100
+		}
+
+		ranking = append(ranking, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar mais negociados: %w", err)
+	}
+
+	return ranking, nil
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/jeovahfialho/b3-analyzer/internal/ingestion"
+	"github.com/jeovahfialho/b3-analyzer/pkg/metrics"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	TradeSizeByQuantity   = "quantity"
+	TradeSizeByFinancial  = "financial"
+	TradeSizeByPercentile = "percentile"
+)
+
+// BlockTradeMinFinancial é o mesmo limite de block_volume em
+// daily_aggregations, usado como padrão do modo financial.
+var BlockTradeMinFinancial = decimal.NewFromInt(ingestion.BlockTradeMinFinancial)
+
+var tradeSizeDefaults = map[string]struct {
+	edges []int64
+	block int64
+}{
+	TradeSizeByQuantity:   {[]int64{100, 1000, 10000, 100000}, 100000},
+	TradeSizeByFinancial:  {[]int64{10000, 100000, 1000000, 10000000}, BlockTradeMinFinancial.IntPart()},
+	TradeSizeByPercentile: {[]int64{50, 90, 99}, 99},
+}
+
+func ValidTradeSizeBy(by string) bool {
+	_, ok := tradeSizeDefaults[by]
+	return ok
+}
+
+// GetTradeSizes distribui os negócios do período pelas faixas de tamanho,
+// por pregão, e lista até limit negócios em bloco (tamanho >= threshold,
+// ou o percentil threshold no modo percentile), maiores primeiro. Sem
+// threshold, usa o padrão do modo.
+func (s *CandleService) GetTradeSizes(ctx context.Context, ticker string, startDate, endDate time.Time, by string, threshold *decimal.Decimal, limit int) (*domain.TradeSizeAnalysis, error) {
+	defaults, ok := tradeSizeDefaults[by]
+	if !ok {
+		return nil, fmt.Errorf("classificação inválida: %s (use quantity, financial ou percentile)", by)
+	}
+
+	timer := metrics.NewTimer()
+	defer timer.ObserveDuration(metrics.DatabaseQueryDuration.WithLabelValues("trade_sizes"))
+
+	result := &domain.TradeSizeAnalysis{
+		Ticker:      ticker,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		By:          by,
+		Days:        []domain.TradeSizeDay{},
+		BlockTrades: []domain.BlockTrade{},
+	}
+
+	for _, edge := range defaults.edges {
+		result.Edges = append(result.Edges, decimal.NewFromInt(edge))
+	}
+	result.BlockThreshold = decimal.NewFromInt(defaults.block)
+	if threshold != nil {
+		result.BlockThreshold = *threshold
+	}
+
+	metric := "quantidade_negociada::numeric"
+	if by == TradeSizeByFinancial {
+		metric = "preco_negocio * quantidade_negociada"
+	}
+
+	if by == TradeSizeByPercentile {
+		edges, block, err := s.quantityPercentiles(ctx, ticker, startDate, endDate, result.Edges, result.BlockThreshold)
+		if err != nil {
+			return nil, err
+		}
+		result.Edges, result.BlockThreshold = edges, block
+	}
+
+	query := fmt.Sprintf(`
+        SELECT
+            data_negocio,
+            width_bucket(%[1]s, $4::numeric[]) as bucket,
+            COUNT(*) as trades,
+            SUM(quantidade_negociada) as volume,
+            COUNT(*) FILTER (WHERE %[1]s >= $5) as block_trades,
+            COALESCE(SUM(quantidade_negociada) FILTER (WHERE %[1]s >= $5), 0) as block_volume
+        FROM trades
+        WHERE codigo_instrumento = $1
+        AND data_negocio BETWEEN $2 AND $3
+        GROUP BY data_negocio, bucket
+        ORDER BY data_negocio, bucket
+    `, metric)
+
+	rows, err := s.pool.Query(ctx, query, ticker, startDate, endDate, result.Edges, result.BlockThreshold)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("trade_sizes", "error").Inc()
+		return nil, fmt.Errorf("erro ao buscar distribuição de tamanhos: %w", err)
+	}
+	defer rows.Close()
+
+	var day *domain.TradeSizeDay
+	for rows.Next() {
+		var date time.Time
+		var bucket, trades, blockTrades int
+		var volume, blockVolume int64
+
+		if err := rows.Scan(&date, &bucket, &trades, &volume, &blockTrades, &blockVolume); err != nil {
+			return nil, fmt.Errorf("erro ao escanear distribuição de tamanhos: %w", err)
+		}
+
+		if day == nil || !day.Date.Equal(date) {
+			result.Days = append(result.Days, domain.TradeSizeDay{
+				Date:    date,
+				Buckets: tradeSizeBuckets(result.Edges, by),
+			})
+			day = &result.Days[len(result.Days)-1]
+		}
+
+		day.Buckets[bucket].Trades += trades
+		day.Buckets[bucket].Volume += volume
+		day.Trades += trades
+		day.Volume += volume
+		day.BlockTrades += blockTrades
+		day.BlockVolume += blockVolume
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar distribuição de tamanhos: %w", err)
+	}
+
+	if len(result.Days) == 0 {
+		return nil, ErrNotFound
+	}
+
+	for i := range result.Days {
+		day := &result.Days[i]
+		if day.Volume == 0 {
+			continue
+		}
+		for j := range day.Buckets {
+			day.Buckets[j].VolumePercent = float64(day.Buckets[j].Volume) / float64(day.Volume) * 100
+		}
+		day.BlockVolumePercent = float64(day.BlockVolume) / float64(day.Volume) * 100
+	}
+
+	if result.BlockTrades, err = s.queryBlockTrades(ctx, ticker, startDate, endDate, metric, result.BlockThreshold, limit); err != nil {
+		return nil, err
+	}
+
+	metrics.DatabaseQueries.WithLabelValues("trade_sizes", "success").Inc()
+	return result, nil
+}
+
+// quantityPercentiles converte os percentis (0-100) das faixas e do bloco
+// em quantidades pela distribuição do ticker no período.
+func (s *CandleService) quantityPercentiles(ctx context.Context, ticker string, startDate, endDate time.Time, percentiles []decimal.Decimal, block decimal.Decimal) ([]decimal.Decimal, decimal.Decimal, error) {
+	fractions := make([]float64, 0, len(percentiles)+1)
+	for _, p := range append(append([]decimal.Decimal{}, percentiles...), block) {
+		fractions = append(fractions, p.InexactFloat64()/100)
+	}
+
+	query := `
+        SELECT PERCENTILE_CONT($4::float8[]) WITHIN GROUP (ORDER BY quantidade_negociada)
+        FROM trades
+        WHERE codigo_instrumento = $1
+        AND data_negocio BETWEEN $2 AND $3
+    `
+
+	var values []*float64
+	if err := s.pool.QueryRow(ctx, query, ticker, startDate, endDate, fractions).Scan(&values); err != nil {
+		metrics.DatabaseQueries.WithLabelValues("trade_sizes", "error").Inc()
+		return nil, decimal.Zero, fmt.Errorf("erro ao calcular percentis: %w", err)
+	}
+
+	if len(values) != len(fractions) || values[0] == nil {
+		return nil, decimal.Zero, ErrNotFound
+	}
+
+	edges := make([]decimal.Decimal, len(percentiles))
+	for i := range edges {
+		edges[i] = decimal.NewFromFloat(*values[i]).Round(0)
+	}
+
+	return edges, decimal.NewFromFloat(*values[len(values)-1]).Round(0), nil
+}
+
+func (s *CandleService) queryBlockTrades(ctx context.Context, ticker string, startDate, endDate time.Time, metric string, threshold decimal.Decimal, limit int) ([]domain.BlockTrade, error) {
+	query := fmt.Sprintf(`
+        SELECT
+            data_negocio,
+            to_char(hora_fechamento, 'HH24:MI:SS.MS') as hora,
+            preco_negocio,
+            quantidade_negociada,
+            preco_negocio * quantidade_negociada as financeiro
+        FROM trades
+        WHERE codigo_instrumento = $1
+        AND data_negocio BETWEEN $2 AND $3
+        AND %s >= $4
+        ORDER BY financeiro DESC, data_negocio, hora_fechamento
+        LIMIT $5
+    `, metric)
+
+	rows, err := s.pool.Query(ctx, query, ticker, startDate, endDate, threshold, limit)
+	if err != nil {
+		metrics.DatabaseQueries.WithLabelValues("trade_sizes", "error").Inc()
+		return nil, fmt.Errorf("erro ao buscar negócios em bloco: %w", err)
+	}
+	defer rows.Close()
+
+	blocks := []domain.BlockTrade{}
+	for rows.Next() {
+		var block domain.BlockTrade
+		if err := rows.Scan(&block.Date, &block.Time, &block.Price, &block.Quantity, &block.Financial); err != nil {
+			return nil, fmt.Errorf("erro ao escanear negócio em bloco: %w", err)
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks, rows.Err()
+}
+
+// tradeSizeBuckets monta as len(edges)+1 faixas na ordem de width_bucket:
+// a faixa 0 fica abaixo do primeiro limite.
+func tradeSizeBuckets(edges []decimal.Decimal, by string) []domain.TradeSizeBucket {
+	format := func(d decimal.Decimal) string {
+		if by == TradeSizeByFinancial {
+			return "R$ " + d.String()
+		}
+		return d.String()
+	}
+
+	buckets := make([]domain.TradeSizeBucket, len(edges)+1)
+	for i := range buckets {
+		switch {
+		case i == 0:
+			buckets[i].Max = &edges[0]
+			buckets[i].Label = "< " + format(edges[0])
+		case i == len(edges):
+			buckets[i].Min = &edges[i-1]
+			buckets[i].Label = ">= " + format(edges[i-1])
+		default:
+			buckets[i].Min = &edges[i-1]
+			buckets[i].Max = &edges[i]
+			buckets[i].Label = format(edges[i-1]) + " - " + format(edges[i])
+		}
+	}
+
+	return buckets
+}
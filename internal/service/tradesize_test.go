@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestTradeSizeBuckets(t *testing.T) {
+	edges := []decimal.Decimal{decimal.NewFromInt(100), decimal.NewFromInt(1000)}
+
+	buckets := tradeSizeBuckets(edges, TradeSizeByQuantity)
+	if len(buckets) != 3 {
+		t.Fatalf("esperado 3 faixas, recebido %d", len(buckets))
+	}
+
+	labels := []string{"< 100", "100 - 1000", ">= 1000"}
+	for i, bucket := range buckets {
+		if bucket.Label != labels[i] {
+			t.Errorf("faixa %d: esperado %q, recebido %q", i, labels[i], bucket.Label)
+		}
+	}
+
+	if buckets[0].Min != nil || buckets[2].Max != nil || !buckets[1].Min.Equal(edges[0]) {
+		t.Errorf("limites inesperados: %+v", buckets)
+	}
+}
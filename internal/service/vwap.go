@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// ParseTimeOfDay converte HH:MM ou HH:MM:SS no deslocamento desde a
+// meia-noite do pregão.
+func ParseTimeOfDay(value string) (time.Duration, error) {
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return time.Duration(t.Hour())*time.Hour +
+				time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second, nil
+		}
+	}
+	return 0, fmt.Errorf("horário inválido: %s (use HH:MM ou HH:MM:SS)", value)
+}
+
+func ValidateFills(fills []domain.Fill) error {
+	for i, fill := range fills {
+		side := strings.ToLower(fill.Side)
+		if side != "buy" && side != "sell" {
+			return fmt.Errorf("fill %d: side deve ser buy ou sell", i)
+		}
+		if fill.Quantity <= 0 {
+			return fmt.Errorf("fill %d: quantidade deve ser positiva", i)
+		}
+		if !fill.Price.IsPositive() {
+			return fmt.Errorf("fill %d: preço deve ser positivo", i)
+		}
+		if fill.Time != "" {
+			if _, err := ParseTimeOfDay(fill.Time); err != nil {
+				return fmt.Errorf("fill %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// GetVWAPCurve calcula o VWAP e o TWAP acumulados do pregão na janela
+// [from, to). Sem janela explícita, usa o intervalo coberto pelos horários
+// dos fills ou, na falta deles, o pregão inteiro. Os fills informados são
+// comparados com o VWAP/TWAP final da janela.
+func (s *CandleService) GetVWAPCurve(ctx context.Context, ticker string, date time.Time, from, to, interval string, fills []domain.Fill) (*domain.VWAPCurve, error) {
+	step, err := ParseCandleInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateFills(fills); err != nil {
+		return nil, err
+	}
+
+	if from == "" && to == "" {
+		from, to = fillsWindow(fills, step)
+	}
+
+	start, end := time.Duration(0), 24*time.Hour
+	if from != "" {
+		if start, err = ParseTimeOfDay(from); err != nil {
+			return nil, err
+		}
+	}
+	if to != "" {
+		if end, err = ParseTimeOfDay(to); err != nil {
+			return nil, err
+		}
+	}
+
+	candles, err := s.QueryCandles(ctx, ticker, date, date, step)
+	if err != nil {
+		return nil, err
+	}
+
+	window := make([]domain.Candle, 0, len(candles))
+	for _, candle := range fillCandles(candles, step) {
+		offset := candle.Time.Sub(date)
+		if offset >= start && offset < end {
+			window = append(window, candle)
+		}
+	}
+
+	curve := &domain.VWAPCurve{
+		Ticker:   ticker,
+		Date:     date,
+		From:     from,
+		To:       to,
+		Interval: interval,
+		Points:   buildVWAPCurve(window),
+	}
+
+	if len(curve.Points) > 0 {
+		last := curve.Points[len(curve.Points)-1]
+		curve.VWAP = last.VWAP
+		curve.TWAP = last.TWAP
+		curve.Volume = last.CumulativeVolume
+	}
+
+	if len(fills) > 0 && len(curve.Points) > 0 {
+		curve.Benchmarks = benchmarkFills(fills, curve.VWAP, curve.TWAP, curve.Volume)
+	}
+
+	return curve, nil
+}
+
+func buildVWAPCurve(candles []domain.Candle) []domain.VWAPPoint {
+	points := make([]domain.VWAPPoint, 0, len(candles))
+
+	var cumVolume int64
+	cumFinancial := decimal.Zero
+	closeSum := decimal.Zero
+
+	for i, candle := range candles {
+		cumVolume += candle.Volume
+		cumFinancial = cumFinancial.Add(candle.FinancialVolume)
+		closeSum = closeSum.Add(candle.Close)
+
+		point := domain.VWAPPoint{
+			Time:             candle.Time,
+			Close:            candle.Close,
+			Volume:           candle.Volume,
+			CumulativeVolume: cumVolume,
+			TWAP:             closeSum.Div(decimal.NewFromInt(int64(i + 1))).Round(4),
+		}
+
+		if cumVolume > 0 {
+			point.VWAP = cumFinancial.Div(decimal.NewFromInt(cumVolume)).Round(4)
+		}
+
+		points = append(points, point)
+	}
+
+	return points
+}
+
+func benchmarkFills(fills []domain.Fill, vwap, twap decimal.Decimal, marketVolume int64) []domain.ExecutionBenchmark {
+	var benchmarks []domain.ExecutionBenchmark
+
+	for _, side := range []string{"buy", "sell"} {
+		var quantity int64
+		notional := decimal.Zero
+		count := 0
+
+		for _, fill := range fills {
+			if strings.ToLower(fill.Side) != side {
+				continue
+			}
+			count++
+			quantity += fill.Quantity
+			notional = notional.Add(fill.Price.Mul(decimal.NewFromInt(fill.Quantity)))
+		}
+
+		if count == 0 {
+			continue
+		}
+
+		avgPrice := notional.Div(decimal.NewFromInt(quantity))
+
+		// slippage positivo significa execução pior que o benchmark
+		sign := 1.0
+		if side == "sell" {
+			sign = -1.0
+		}
+
+		benchmark := domain.ExecutionBenchmark{
+			Side:     side,
+			Fills:    count,
+			Quantity: quantity,
+			AvgPrice: avgPrice.Round(4),
+			VWAP:     vwap,
+			TWAP:     twap,
+		}
+
+		if vwap.IsPositive() {
+			benchmark.SlippageVWAPBps = sign * avgPrice.Sub(vwap).Div(vwap).InexactFloat64() * 10000
+		}
+		if twap.IsPositive() {
+			benchmark.SlippageTWAPBps = sign * avgPrice.Sub(twap).Div(twap).InexactFloat64() * 10000
+		}
+		if marketVolume > 0 {
+			benchmark.ParticipationRate = float64(quantity) / float64(marketVolume)
+		}
+
+		benchmarks = append(benchmarks, benchmark)
+	}
+
+	return benchmarks
+}
+
+// fillsWindow devolve a janela que cobre os horários dos fills, do início
+// da janela do primeiro ao fim da janela do último.
+func fillsWindow(fills []domain.Fill, step time.Duration) (string, string) {
+	var first, last time.Duration
+	found := false
+
+	for _, fill := range fills {
+		if fill.Time == "" {
+			continue
+		}
+		offset, err := ParseTimeOfDay(fill.Time)
+		if err != nil {
+			continue
+		}
+		if !found || offset < first {
+			first = offset
+		}
+		if !found || offset > last {
+			last = offset
+		}
+		found = true
+	}
+
+	if !found {
+		return "", ""
+	}
+
+	first = first.Truncate(step)
+	last = last.Truncate(step) + step
+
+	return formatTimeOfDay(first), formatTimeOfDay(last)
+}
+
+func formatTimeOfDay(d time.Duration) string {
+	return time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC).Add(d).Format("15:04:05")
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestBuildVWAPCurve(t *testing.T) {
+	base := time.Date(2025, 5, 20, 10, 0, 0, 0, time.UTC)
+
+	candles := []domain.Candle{
+		{Time: base, Close: decimal.NewFromInt(10), Volume: 100, FinancialVolume: decimal.NewFromInt(1000)},
+		{Time: base.Add(time.Minute), Close: decimal.NewFromInt(10), Filled: true},
+		{Time: base.Add(2 * time.Minute), Close: decimal.NewFromInt(13), Volume: 300, FinancialVolume: decimal.NewFromInt(3900)},
+	}
+
+	points := buildVWAPCurve(candles)
+
+	last := points[len(points)-1]
+	if !last.VWAP.Equal(decimal.RequireFromString("12.25")) {
+		t.Errorf("VWAP esperado 12.25, recebido %s", last.VWAP)
+	}
+	if !last.TWAP.Equal(decimal.NewFromInt(11)) {
+		t.Errorf("TWAP esperado 11, recebido %s", last.TWAP)
+	}
+	if last.CumulativeVolume != 400 {
+		t.Errorf("volume acumulado esperado 400, recebido %d", last.CumulativeVolume)
+	}
+}
+
+func TestBenchmarkFills(t *testing.T) {
+	fills := []domain.Fill{
+		{Side: "buy", Price: decimal.RequireFromString("10.10"), Quantity: 100},
+		{Side: "sell", Price: decimal.RequireFromString("9.90"), Quantity: 100},
+	}
+
+	benchmarks := benchmarkFills(fills, decimal.NewFromInt(10), decimal.NewFromInt(10), 1000)
+	if len(benchmarks) != 2 {
+		t.Fatalf("esperado um benchmark por lado, recebido %d", len(benchmarks))
+	}
+
+	for _, b := range benchmarks {
+		if math.Abs(b.SlippageVWAPBps-100) > 1e-9 {
+			t.Errorf("%s: slippage esperado 100 bps, recebido %f", b.Side, b.SlippageVWAPBps)
+		}
+		if b.ParticipationRate != 0.1 {
+			t.Errorf("%s: participação esperada 0.1, recebida %f", b.Side, b.ParticipationRate)
+		}
+	}
+}
@@ -100,6 +100,15 @@ func (c *RedisCache) DeletePattern(ctx context.Context, pattern string) error {
 	return nil
 }
 
+// Client expõe o cliente para serviços que usam comandos do Redis
+// diretamente. Seguro para chamar com cache nil.
+func (c *RedisCache) Client() *redis.Client {
+	if c == nil {
+		return nil
+	}
+	return c.client
+}
+
 func (c *RedisCache) Close() error {
 	return c.client.Close()
 }
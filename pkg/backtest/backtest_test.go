@@ -0,0 +1,105 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/pkg/indicators"
+)
+
+func makeBars(closes []float64) []indicators.Bar {
+	day := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	bars := make([]indicators.Bar, len(closes))
+	for i, c := range closes {
+		bars[i] = indicators.Bar{Date: day.AddDate(0, 0, i), Open: c, High: c, Low: c, Close: c, Volume: 1000}
+	}
+	return bars
+}
+
+func TestParseOperand(t *testing.T) {
+	valid := []string{"close", "40", "-1.5", "sma:20", "bbands:20:2.5.upper", "macd.signal"}
+	for _, raw := range valid {
+		if _, err := parseOperand(raw); err != nil {
+			t.Errorf("%s deveria ser válido: %v", raw, err)
+		}
+	}
+
+	// sem saída explícita, indicadores com várias saídas usam a principal
+	defaults := map[string]string{"macd": "macd", "bbands:20:2": "middle", "rsi:14": "value"}
+	for raw, want := range defaults {
+		op, err := parseOperand(raw)
+		if err != nil {
+			t.Errorf("%s deveria ser válido: %v", raw, err)
+			continue
+		}
+		if op.output != want {
+			t.Errorf("%s: esperado saída %s, recebido %s", raw, want, op.output)
+		}
+	}
+
+	invalid := []string{"", "foo", "sma:20.upper", "bbands:20:2.value"}
+	for _, raw := range invalid {
+		if _, err := parseOperand(raw); err == nil {
+			t.Errorf("%s deveria ser inválido", raw)
+		}
+	}
+}
+
+func TestRunExecutesOnNextOpen(t *testing.T) {
+	strategy := Strategy{
+		Entry:          []Condition{{Left: "close", Op: "crosses_above", Right: "10"}},
+		Exit:           []Condition{{Left: "close", Op: "<", Right: "11"}},
+		Sizing:         Sizing{Method: SizingFixedQuantity, Value: 100},
+		InitialCapital: 10000,
+	}
+
+	bars := makeBars([]float64{9, 10.5, 12, 13, 10.8, 10})
+	result, err := Run(strategy, map[string]Series{"PETR4": {Bars: bars}})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	if len(result.Trades) != 1 {
+		t.Fatalf("esperado 1 trade, recebido %d", len(result.Trades))
+	}
+
+	trade := result.Trades[0]
+	// sinal em 10.5 executa na abertura seguinte (12); saída sinalizada em 10.8 executa a 10
+	if trade.EntryPrice != 12 || trade.ExitPrice != 10 || trade.ExitReason != ExitSignal {
+		t.Errorf("trade inesperado: %+v", trade)
+	}
+	if math.Abs(result.Metrics.FinalEquity-9800) > 1e-9 {
+		t.Errorf("patrimônio final esperado 9800, recebido %f", result.Metrics.FinalEquity)
+	}
+	if result.Metrics.HitRate == nil || *result.Metrics.HitRate != 0 {
+		t.Errorf("hit rate esperado 0")
+	}
+	if result.Metrics.MaxDrawdown <= 0 {
+		t.Errorf("drawdown deveria ser positivo")
+	}
+}
+
+func TestRunStopLossAndCosts(t *testing.T) {
+	strategy := Strategy{
+		Entry:          []Condition{{Left: "close", Op: ">", Right: "0"}},
+		StopLoss:       5,
+		Sizing:         Sizing{Method: SizingPercentEquity, Value: 100, LotSize: 100},
+		InitialCapital: 10000,
+		Costs:          Costs{CommissionFixed: 10},
+	}
+
+	bars := makeBars([]float64{10, 10, 9, 9})
+	result, err := Run(strategy, map[string]Series{"VALE3": {Bars: bars}})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	trade := result.Trades[0]
+	if trade.ExitReason != ExitStopLoss || trade.Quantity != 900 {
+		t.Errorf("esperado stop com 900 ações, recebido %+v", trade)
+	}
+	if trade.Costs != 20 {
+		t.Errorf("custos esperados 20, recebido %f", trade.Costs)
+	}
+}
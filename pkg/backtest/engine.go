@@ -0,0 +1,468 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jeovahfialho/b3-analyzer/pkg/indicators"
+	"github.com/jeovahfialho/b3-analyzer/pkg/stats"
+)
+
+const (
+	ExitSignal     = "signal"
+	ExitStopLoss   = "stop_loss"
+	ExitTakeProfit = "take_profit"
+	ExitEndOfTest  = "end_of_test"
+)
+
+// Series são os pregões de um ticker em ordem cronológica; os anteriores a
+// Start servem apenas para aquecer os indicadores.
+type Series struct {
+	Bars  []indicators.Bar
+	Start int
+}
+
+type Trade struct {
+	Ticker     string    `json:"ticker"`
+	EntryDate  time.Time `json:"entry_date"`
+	EntryPrice float64   `json:"entry_price"`
+	ExitDate   time.Time `json:"exit_date"`
+	ExitPrice  float64   `json:"exit_price"`
+	Quantity   int64     `json:"quantity"`
+	Costs      float64   `json:"costs"`
+	PnL        float64   `json:"pnl"`
+	ReturnPct  float64   `json:"return_pct"`
+	BarsHeld   int       `json:"bars_held"`
+	ExitReason string    `json:"exit_reason"`
+}
+
+type EquityPoint struct {
+	Date     time.Time `json:"date"`
+	Equity   float64   `json:"equity"`
+	Cash     float64   `json:"cash"`
+	Drawdown float64   `json:"drawdown"`
+}
+
+type Metrics struct {
+	InitialCapital float64  `json:"initial_capital"`
+	FinalEquity    float64  `json:"final_equity"`
+	TotalReturn    float64  `json:"total_return"`
+	CAGR           *float64 `json:"cagr"`
+	Sharpe         *float64 `json:"sharpe"`
+	MaxDrawdown    float64  `json:"max_drawdown"`
+	HitRate        *float64 `json:"hit_rate"`
+	Trades         int      `json:"trades"`
+	AvgTradeReturn *float64 `json:"avg_trade_return"`
+	Exposure       float64  `json:"exposure"`
+	Sessions       int      `json:"sessions"`
+}
+
+type Result struct {
+	Strategy Strategy      `json:"strategy"`
+	Metrics  Metrics       `json:"metrics"`
+	Equity   []EquityPoint `json:"equity"`
+	Trades   []Trade       `json:"trades"`
+}
+
+// compiled guarda, por ticker, as séries dos operandos já calculadas.
+type compiled struct {
+	bars   []indicators.Bar
+	values map[string][]float64
+}
+
+func (c *compiled) value(raw string, i int) float64 {
+	if i < 0 {
+		return math.NaN()
+	}
+	return c.values[raw][i]
+}
+
+func compile(strategy Strategy, series Series) *compiled {
+	c := &compiled{bars: series.Bars, values: map[string][]float64{}}
+	computed := map[string]map[string][]float64{}
+
+	for _, cond := range append(append([]Condition{}, strategy.Entry...), strategy.Exit...) {
+		for _, raw := range []string{cond.Left, cond.Right} {
+			if _, ok := c.values[raw]; ok {
+				continue
+			}
+
+			op, _ := parseOperand(raw)
+			values := make([]float64, len(series.Bars))
+
+			switch {
+			case op.spec != nil:
+				key := op.spec.Key()
+				if computed[key] == nil {
+					computed[key] = indicators.Compute(*op.spec, series.Bars)
+				}
+				copy(values, computed[key][op.output])
+			case op.field != "":
+				for i, bar := range series.Bars {
+					values[i] = barField(bar, op.field)
+				}
+			default:
+				for i := range values {
+					values[i] = op.constant
+				}
+			}
+
+			c.values[raw] = values
+		}
+	}
+
+	return c
+}
+
+func barField(bar indicators.Bar, field string) float64 {
+	switch field {
+	case "open":
+		return bar.Open
+	case "high":
+		return bar.High
+	case "low":
+		return bar.Low
+	case "volume":
+		return bar.Volume
+	}
+	return bar.Close
+}
+
+func (c *compiled) holds(cond Condition, i int) bool {
+	left, right := c.value(cond.Left, i), c.value(cond.Right, i)
+	if math.IsNaN(left) || math.IsNaN(right) {
+		return false
+	}
+
+	switch cond.Op {
+	case ">":
+		return left > right
+	case ">=":
+		return left >= right
+	case "<":
+		return left < right
+	case "<=":
+		return left <= right
+	case "crosses_above", "crosses_below":
+		prevLeft, prevRight := c.value(cond.Left, i-1), c.value(cond.Right, i-1)
+		if math.IsNaN(prevLeft) || math.IsNaN(prevRight) {
+			return false
+		}
+		if cond.Op == "crosses_above" {
+			return prevLeft <= prevRight && left > right
+		}
+		return prevLeft >= prevRight && left < right
+	}
+	return false
+}
+
+type position struct {
+	quantity   int64
+	entryPrice float64
+	entryDate  time.Time
+	entryIdx   int
+	entryCost  float64
+}
+
+type order struct {
+	ticker string
+	exit   bool
+	reason string
+}
+
+// Run simula a estratégia sobre os tickers, com um único caixa e no máximo
+// uma posição por ticker. As posições abertas no último pregão são
+// encerradas no fechamento, com custos.
+func Run(strategy Strategy, series map[string]Series) (*Result, error) {
+	if err := strategy.Validate(); err != nil {
+		return nil, err
+	}
+
+	tickers := make([]string, 0, len(series))
+	for ticker := range series {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+
+	books := make(map[string]*compiled, len(tickers))
+	index := make(map[string]map[time.Time]int, len(tickers))
+	dateSet := map[time.Time]bool{}
+
+	for _, ticker := range tickers {
+		books[ticker] = compile(strategy, series[ticker])
+		index[ticker] = map[time.Time]int{}
+		for i, bar := range series[ticker].Bars {
+			index[ticker][bar.Date] = i
+			if i >= series[ticker].Start {
+				dateSet[bar.Date] = true
+			}
+		}
+	}
+
+	if len(dateSet) == 0 {
+		return nil, fmt.Errorf("nenhum pregão no período")
+	}
+
+	dates := make([]time.Time, 0, len(dateSet))
+	for d := range dateSet {
+		dates = append(dates, d)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	lot := strategy.Sizing.LotSize
+	if lot <= 0 {
+		lot = 1
+	}
+
+	cash := strategy.InitialCapital
+	positions := map[string]*position{}
+	lastClose := map[string]float64{}
+	var pending []order
+	var trades []Trade
+	equity := make([]EquityPoint, 0, len(dates))
+	exposed := 0
+
+	closePosition := func(ticker string, pos *position, date time.Time, idx int, price float64, reason string) {
+		notional := price * float64(pos.quantity)
+		cost := commission(strategy.Costs, notional)
+		cash += notional - cost
+
+		pnl := notional - cost - pos.entryPrice*float64(pos.quantity) - pos.entryCost
+		trades = append(trades, Trade{
+			Ticker:     ticker,
+			EntryDate:  pos.entryDate,
+			EntryPrice: pos.entryPrice,
+			ExitDate:   date,
+			ExitPrice:  price,
+			Quantity:   pos.quantity,
+			Costs:      pos.entryCost + cost,
+			PnL:        pnl,
+			ReturnPct:  pnl / (pos.entryPrice*float64(pos.quantity) + pos.entryCost) * 100,
+			BarsHeld:   idx - pos.entryIdx,
+			ExitReason: reason,
+		})
+		delete(positions, ticker)
+	}
+
+	for _, date := range dates {
+		// ordens do pregão anterior executam na abertura: saídas antes para
+		// liberar caixa
+		sort.SliceStable(pending, func(i, j int) bool { return pending[i].exit && !pending[j].exit })
+
+		var carried []order
+		for _, o := range pending {
+			idx, ok := index[o.ticker][date]
+			if !ok {
+				carried = append(carried, o)
+				continue
+			}
+			open := books[o.ticker].bars[idx].Open
+
+			if o.exit {
+				if pos := positions[o.ticker]; pos != nil {
+					closePosition(o.ticker, pos, date, idx, open*(1-strategy.Costs.SlippageBps/10000), o.reason)
+				}
+				continue
+			}
+
+			if positions[o.ticker] != nil {
+				continue
+			}
+
+			price := open * (1 + strategy.Costs.SlippageBps/10000)
+			quantity := sizeOrder(strategy, price, markToMarket(cash, positions, lastClose), cash, lot)
+			if quantity <= 0 {
+				continue
+			}
+
+			notional := price * float64(quantity)
+			cost := commission(strategy.Costs, notional)
+			cash -= notional + cost
+			positions[o.ticker] = &position{
+				quantity:   quantity,
+				entryPrice: price,
+				entryDate:  date,
+				entryIdx:   idx,
+				entryCost:  cost,
+			}
+		}
+		pending = carried
+
+		for _, ticker := range tickers {
+			if idx, ok := index[ticker][date]; ok {
+				lastClose[ticker] = books[ticker].bars[idx].Close
+			}
+		}
+
+		if len(positions) > 0 {
+			exposed++
+		}
+		equity = append(equity, EquityPoint{
+			Date:   date,
+			Equity: markToMarket(cash, positions, lastClose),
+			Cash:   cash,
+		})
+
+		// sinais no fechamento
+		for _, ticker := range tickers {
+			idx, ok := index[ticker][date]
+			if !ok {
+				continue
+			}
+			book := books[ticker]
+
+			if pos := positions[ticker]; pos != nil {
+				if reason := exitReason(strategy, book, idx, pos); reason != "" {
+					pending = append(pending, order{ticker: ticker, exit: true, reason: reason})
+				}
+				continue
+			}
+
+			if allHold(book, strategy.Entry, idx) {
+				pending = append(pending, order{ticker: ticker})
+			}
+		}
+	}
+
+	last := dates[len(dates)-1]
+	for _, ticker := range tickers {
+		if pos := positions[ticker]; pos != nil {
+			idx, ok := index[ticker][last]
+			if !ok {
+				idx = len(books[ticker].bars) - 1
+			}
+			price := lastClose[ticker] * (1 - strategy.Costs.SlippageBps/10000)
+			closePosition(ticker, pos, last, idx, price, ExitEndOfTest)
+		}
+	}
+	equity[len(equity)-1].Equity = cash
+	equity[len(equity)-1].Cash = cash
+
+	return &Result{
+		Strategy: strategy,
+		Metrics:  computeMetrics(strategy.InitialCapital, equity, trades, exposed),
+		Equity:   equity,
+		Trades:   trades,
+	}, nil
+}
+
+func allHold(book *compiled, conditions []Condition, idx int) bool {
+	for _, cond := range conditions {
+		if !book.holds(cond, idx) {
+			return false
+		}
+	}
+	return true
+}
+
+func exitReason(strategy Strategy, book *compiled, idx int, pos *position) string {
+	closePrice := book.bars[idx].Close
+
+	if strategy.StopLoss > 0 && closePrice <= pos.entryPrice*(1-strategy.StopLoss/100) {
+		return ExitStopLoss
+	}
+	if strategy.TakeProfit > 0 && closePrice >= pos.entryPrice*(1+strategy.TakeProfit/100) {
+		return ExitTakeProfit
+	}
+	for _, cond := range strategy.Exit {
+		if book.holds(cond, idx) {
+			return ExitSignal
+		}
+	}
+	return ""
+}
+
+func sizeOrder(strategy Strategy, price, equity, cash float64, lot int64) int64 {
+	var quantity float64
+
+	switch strategy.Sizing.Method {
+	case SizingPercentEquity:
+		quantity = equity * strategy.Sizing.Value / 100 / price
+	case SizingFixedAmount:
+		quantity = strategy.Sizing.Value / price
+	case SizingFixedQuantity:
+		quantity = strategy.Sizing.Value
+	}
+
+	// limita ao caixa disponível, já descontando a corretagem
+	affordable := (cash - strategy.Costs.CommissionFixed) / (price * (1 + strategy.Costs.CommissionBps/10000))
+	quantity = math.Min(quantity, affordable)
+
+	return int64(quantity) / lot * lot
+}
+
+func commission(costs Costs, notional float64) float64 {
+	return notional*costs.CommissionBps/10000 + costs.CommissionFixed
+}
+
+func markToMarket(cash float64, positions map[string]*position, lastClose map[string]float64) float64 {
+	total := cash
+	for ticker, pos := range positions {
+		total += float64(pos.quantity) * lastClose[ticker]
+	}
+	return total
+}
+
+func computeMetrics(initial float64, equity []EquityPoint, trades []Trade, exposed int) Metrics {
+	values := make([]float64, len(equity))
+	for i, point := range equity {
+		values[i] = point.Equity
+	}
+
+	peak := math.Inf(-1)
+	for i := range equity {
+		peak = math.Max(peak, values[i])
+		if peak > 0 {
+			equity[i].Drawdown = (peak - values[i]) / peak * 100
+		}
+	}
+
+	final := values[len(values)-1]
+	maxDD, _, _ := stats.MaxDrawdown(append([]float64{initial}, values...))
+
+	m := Metrics{
+		InitialCapital: initial,
+		FinalEquity:    final,
+		TotalReturn:    (final/initial - 1) * 100,
+		CAGR:           percent(stats.CAGR(initial, final, len(values))),
+		Sharpe:         finite(stats.Sharpe(stats.SimpleReturns(append([]float64{initial}, values...)), 0)),
+		MaxDrawdown:    maxDD * 100,
+		Trades:         len(trades),
+		Exposure:       float64(exposed) / float64(len(values)) * 100,
+		Sessions:       len(values),
+	}
+
+	if len(trades) > 0 {
+		wins := 0
+		returns := make([]float64, len(trades))
+		for i, trade := range trades {
+			if trade.PnL > 0 {
+				wins++
+			}
+			returns[i] = trade.ReturnPct
+		}
+		hitRate := float64(wins) / float64(len(trades)) * 100
+		avg := stats.Mean(returns)
+		m.HitRate = &hitRate
+		m.AvgTradeReturn = &avg
+	}
+
+	return m
+}
+
+func finite(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
+
+func percent(v float64) *float64 {
+	if p := finite(v); p != nil {
+		*p *= 100
+		return p
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package backtest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jeovahfialho/b3-analyzer/pkg/indicators"
+)
+
+const (
+	SizingPercentEquity = "percent_equity"
+	SizingFixedAmount   = "fixed_amount"
+	SizingFixedQuantity = "fixed_quantity"
+)
+
+// Strategy é a descrição declarativa de uma estratégia comprada. Os sinais
+// são avaliados no fechamento e executados na abertura do pregão seguinte.
+// A entrada exige todas as condições de Entry; a saída, qualquer uma de
+// Exit ou o stop/alvo.
+type Strategy struct {
+	Name           string      `json:"name"`
+	Entry          []Condition `json:"entry"`
+	Exit           []Condition `json:"exit"`
+	StopLoss       float64     `json:"stop_loss,omitempty"`
+	TakeProfit     float64     `json:"take_profit,omitempty"`
+	Sizing         Sizing      `json:"sizing"`
+	InitialCapital float64     `json:"initial_capital"`
+	Costs          Costs       `json:"costs"`
+}
+
+// Condition compara dois operandos. Um operando é um campo do pregão
+// (open, high, low, close, volume), um número ou um indicador no formato
+// de indicators.ParseSpec com a saída opcional após o ponto, por exemplo
+// sma:20, rsi:14 ou bbands:20:2.upper. Sem saída, macd usa a linha macd e
+// bbands a banda central (middle).
+type Condition struct {
+	Left  string `json:"left"`
+	Op    string `json:"op"`
+	Right string `json:"right"`
+}
+
+// Sizing define o tamanho de cada entrada. Value é percentual do
+// patrimônio, valor em reais ou quantidade, conforme Method. A quantidade é
+// arredondada para baixo ao múltiplo de LotSize.
+type Sizing struct {
+	Method  string  `json:"method"`
+	Value   float64 `json:"value"`
+	LotSize int64   `json:"lot_size,omitempty"`
+}
+
+// Costs são aplicados em cada ordem: corretagem percentual (bps) e fixa
+// sobre o financeiro, e slippage (bps) contra o preço de execução.
+type Costs struct {
+	CommissionBps   float64 `json:"commission_bps"`
+	CommissionFixed float64 `json:"commission_fixed"`
+	SlippageBps     float64 `json:"slippage_bps"`
+}
+
+var operators = map[string]bool{
+	">": true, ">=": true, "<": true, "<=": true,
+	"crosses_above": true, "crosses_below": true,
+}
+
+var priceFields = map[string]bool{
+	"open": true, "high": true, "low": true, "close": true, "volume": true,
+}
+
+// defaultOutputs é a saída principal dos indicadores que não têm "value".
+var defaultOutputs = map[string]string{
+	"macd":   "macd",
+	"bbands": "middle",
+}
+
+type operand struct {
+	field    string
+	constant float64
+	spec     *indicators.Spec
+	output   string
+}
+
+func parseOperand(raw string) (operand, error) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if raw == "" {
+		return operand{}, fmt.Errorf("operando vazio")
+	}
+
+	if priceFields[raw] {
+		return operand{field: raw}, nil
+	}
+
+	if v, err := strconv.ParseFloat(raw, 64); err == nil {
+		return operand{constant: v}, nil
+	}
+
+	name, output := raw, ""
+	if i := strings.LastIndex(raw, "."); i >= 0 {
+		if _, err := strconv.ParseFloat(raw[i+1:], 64); err != nil {
+			name, output = raw[:i], raw[i+1:]
+		}
+	}
+
+	spec, err := indicators.ParseSpec(name)
+	if err != nil {
+		return operand{}, err
+	}
+
+	outputs := indicators.Compute(spec, nil)
+	if output == "" {
+		output = defaultOutputs[spec.Name]
+	}
+	if output == "" {
+		output = "value"
+	}
+	if _, ok := outputs[output]; !ok {
+		return operand{}, fmt.Errorf("saída inválida para %s: %s", spec.Name, output)
+	}
+
+	return operand{spec: &spec, output: output}, nil
+}
+
+func (s Strategy) Validate() error {
+	if len(s.Entry) == 0 {
+		return fmt.Errorf("a estratégia precisa de ao menos uma condição de entrada")
+	}
+	if len(s.Exit) == 0 && s.StopLoss == 0 && s.TakeProfit == 0 {
+		return fmt.Errorf("a estratégia precisa de condição de saída, stop_loss ou take_profit")
+	}
+
+	for _, c := range append(append([]Condition{}, s.Entry...), s.Exit...) {
+		if !operators[c.Op] {
+			return fmt.Errorf("operador inválido: %s (use >, >=, <, <=, crosses_above ou crosses_below)", c.Op)
+		}
+		if _, err := parseOperand(c.Left); err != nil {
+			return err
+		}
+		if _, err := parseOperand(c.Right); err != nil {
+			return err
+		}
+	}
+
+	if s.StopLoss < 0 || s.StopLoss >= 100 || s.TakeProfit < 0 {
+		return fmt.Errorf("stop_loss deve estar entre 0 e 100 e take_profit não pode ser negativo")
+	}
+	if s.InitialCapital <= 0 {
+		return fmt.Errorf("initial_capital deve ser positivo")
+	}
+
+	switch s.Sizing.Method {
+	case SizingPercentEquity:
+		if s.Sizing.Value <= 0 || s.Sizing.Value > 100 {
+			return fmt.Errorf("sizing percent_equity deve estar entre 0 e 100")
+		}
+	case SizingFixedAmount, SizingFixedQuantity:
+		if s.Sizing.Value <= 0 {
+			return fmt.Errorf("sizing %s deve ser positivo", s.Sizing.Method)
+		}
+	default:
+		return fmt.Errorf("sizing inválido: %s (use percent_equity, fixed_amount ou fixed_quantity)", s.Sizing.Method)
+	}
+	if s.Sizing.LotSize < 0 {
+		return fmt.Errorf("lot_size não pode ser negativo")
+	}
+
+	if s.Costs.CommissionBps < 0 || s.Costs.CommissionFixed < 0 || s.Costs.SlippageBps < 0 {
+		return fmt.Errorf("custos não podem ser negativos")
+	}
+
+	return nil
+}
+
+// Lookback é quantos pregões antes do início do teste precisam ser
+// carregados para aquecer os indicadores usados nas condições.
+func (s Strategy) Lookback() int {
+	lookback := 0
+	for _, c := range append(append([]Condition{}, s.Entry...), s.Exit...) {
+		for _, raw := range []string{c.Left, c.Right} {
+			op, err := parseOperand(raw)
+			if err != nil || op.spec == nil {
+				continue
+			}
+			if l := op.spec.Lookback(); l > lookback {
+				lookback = l
+			}
+		}
+	}
+	// +1 para que cruzamentos tenham o pregão anterior
+	return lookback + 1
+}
+
+// WithDefaults preenche capital inicial e dimensionamento omitidos: R$ 100
+// mil divididos igualmente entre os tickers.
+func (s Strategy) WithDefaults(tickers int) Strategy {
+	if s.InitialCapital == 0 {
+		s.InitialCapital = 100000
+	}
+	if s.Sizing.Method == "" && tickers > 0 {
+		s.Sizing = Sizing{Method: SizingPercentEquity, Value: 100 / float64(tickers), LotSize: s.Sizing.LotSize}
+	}
+	return s
+}
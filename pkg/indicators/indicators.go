@@ -0,0 +1,190 @@
+package indicators
+
+import (
+	"math"
+	"time"
+)
+
+type Bar struct {
+	Date   time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+func Closes(bars []Bar) []float64 {
+	closes := make([]float64, len(bars))
+	for i, bar := range bars {
+		closes[i] = bar.Close
+	}
+	return closes
+}
+
+func nanSeries(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+// SMA é a média simples dos últimos n valores; os n-1 primeiros são NaN.
+// Valores NaN na entrada reiniciam o aquecimento.
+func SMA(values []float64, n int) []float64 {
+	out := nanSeries(len(values))
+	if n <= 0 {
+		return out
+	}
+
+	sum := 0.0
+	valid := 0
+	for i, v := range values {
+		if math.IsNaN(v) {
+			sum, valid = 0, 0
+			continue
+		}
+		sum += v
+		valid++
+		if valid > n {
+			sum -= values[i-n]
+			valid = n
+		}
+		if valid == n {
+			out[i] = sum / float64(n)
+		}
+	}
+	return out
+}
+
+// EMA usa alfa = 2/(n+1) e é semeada com a SMA dos n primeiros valores
+// válidos. Valores NaN no início da entrada são ignorados.
+func EMA(values []float64, n int) []float64 {
+	return smoothed(values, n, 2/float64(n+1))
+}
+
+// wilder é a média móvel de Wilder (alfa = 1/n), usada por RSI e ATR.
+func wilder(values []float64, n int) []float64 {
+	return smoothed(values, n, 1/float64(n))
+}
+
+func smoothed(values []float64, n int, alpha float64) []float64 {
+	out := nanSeries(len(values))
+	if n <= 0 {
+		return out
+	}
+
+	start := 0
+	for start < len(values) && math.IsNaN(values[start]) {
+		start++
+	}
+	if len(values)-start < n {
+		return out
+	}
+
+	sum := 0.0
+	for i := start; i < start+n; i++ {
+		sum += values[i]
+	}
+	prev := sum / float64(n)
+	out[start+n-1] = prev
+
+	for i := start + n; i < len(values); i++ {
+		prev = alpha*values[i] + (1-alpha)*prev
+		out[i] = prev
+	}
+	return out
+}
+
+// RSI de Wilder sobre n períodos.
+func RSI(closes []float64, n int) []float64 {
+	out := nanSeries(len(closes))
+	if len(closes) <= n {
+		return out
+	}
+
+	gains := nanSeries(len(closes))
+	losses := nanSeries(len(closes))
+	for i := 1; i < len(closes); i++ {
+		change := closes[i] - closes[i-1]
+		gains[i] = math.Max(change, 0)
+		losses[i] = math.Max(-change, 0)
+	}
+
+	avgGain := wilder(gains, n)
+	avgLoss := wilder(losses, n)
+
+	for i := range closes {
+		if math.IsNaN(avgGain[i]) || math.IsNaN(avgLoss[i]) {
+			continue
+		}
+		if avgLoss[i] == 0 {
+			out[i] = 100
+			continue
+		}
+		rs := avgGain[i] / avgLoss[i]
+		out[i] = 100 - 100/(1+rs)
+	}
+	return out
+}
+
+// MACD retorna a linha MACD (EMA rápida - EMA lenta), a linha de sinal
+// (EMA da MACD) e o histograma.
+func MACD(closes []float64, fast, slow, signal int) (macd, signalLine, histogram []float64) {
+	fastEMA := EMA(closes, fast)
+	slowEMA := EMA(closes, slow)
+
+	macd = nanSeries(len(closes))
+	for i := range closes {
+		if !math.IsNaN(fastEMA[i]) && !math.IsNaN(slowEMA[i]) {
+			macd[i] = fastEMA[i] - slowEMA[i]
+		}
+	}
+
+	signalLine = EMA(macd, signal)
+
+	histogram = nanSeries(len(closes))
+	for i := range closes {
+		if !math.IsNaN(macd[i]) && !math.IsNaN(signalLine[i]) {
+			histogram[i] = macd[i] - signalLine[i]
+		}
+	}
+	return macd, signalLine, histogram
+}
+
+// BollingerBands usa a SMA de n períodos e k desvios padrão populacionais.
+func BollingerBands(closes []float64, n int, k float64) (upper, middle, lower []float64) {
+	middle = SMA(closes, n)
+	upper = nanSeries(len(closes))
+	lower = nanSeries(len(closes))
+
+	for i := n - 1; i < len(closes); i++ {
+		if math.IsNaN(middle[i]) {
+			continue
+		}
+		variance := 0.0
+		for j := i - n + 1; j <= i; j++ {
+			d := closes[j] - middle[i]
+			variance += d * d
+		}
+		sd := math.Sqrt(variance / float64(n))
+		upper[i] = middle[i] + k*sd
+		lower[i] = middle[i] - k*sd
+	}
+	return upper, middle, lower
+}
+
+// ATR de Wilder; o true range do primeiro pregão é High - Low.
+func ATR(bars []Bar, n int) []float64 {
+	trueRanges := make([]float64, len(bars))
+	for i, bar := range bars {
+		tr := bar.High - bar.Low
+		if i > 0 {
+			prevClose := bars[i-1].Close
+			tr = math.Max(tr, math.Max(math.Abs(bar.High-prevClose), math.Abs(bar.Low-prevClose)))
+		}
+		trueRanges[i] = tr
+	}
+	return wilder(trueRanges, n)
+}
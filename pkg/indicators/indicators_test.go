@@ -0,0 +1,92 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestSMA(t *testing.T) {
+	got := SMA([]float64{1, 2, 3, 4, 5}, 3)
+
+	if !math.IsNaN(got[0]) || !math.IsNaN(got[1]) {
+		t.Error("os dois primeiros valores deveriam estar em aquecimento")
+	}
+	for i, expected := range map[int]float64{2: 2, 3: 3, 4: 4} {
+		if !almostEqual(got[i], expected) {
+			t.Errorf("SMA[%d]: esperado %f, recebido %f", i, expected, got[i])
+		}
+	}
+}
+
+func TestEMA(t *testing.T) {
+	got := EMA([]float64{1, 2, 3, 4}, 3)
+
+	if !almostEqual(got[2], 2) {
+		t.Errorf("EMA deveria ser semeada com a SMA, recebido %f", got[2])
+	}
+	if !almostEqual(got[3], 0.5*4+0.5*2) {
+		t.Errorf("EMA[3] incorreta: %f", got[3])
+	}
+}
+
+func TestRSI(t *testing.T) {
+	rising := []float64{1, 2, 3, 4, 5, 6}
+	got := RSI(rising, 3)
+
+	if !math.IsNaN(got[2]) {
+		t.Error("RSI deveria precisar de n variações")
+	}
+	if got[3] != 100 {
+		t.Errorf("RSI de série só de altas deveria ser 100, recebido %f", got[3])
+	}
+
+	mixed := RSI([]float64{10, 11, 10, 11}, 2)
+	if !almostEqual(mixed[2], 50) {
+		t.Errorf("RSI com ganho e perda iguais deveria ser 50, recebido %f", mixed[2])
+	}
+}
+
+func TestBollingerBands(t *testing.T) {
+	upper, middle, lower := BollingerBands([]float64{1, 3}, 2, 2)
+
+	if !almostEqual(middle[1], 2) || !almostEqual(upper[1], 4) || !almostEqual(lower[1], 0) {
+		t.Errorf("bandas incorretas: %f %f %f", upper[1], middle[1], lower[1])
+	}
+}
+
+func TestATR(t *testing.T) {
+	bars := []Bar{
+		{High: 10, Low: 8, Close: 9},
+		{High: 12, Low: 10, Close: 11},
+	}
+
+	got := ATR(bars, 2)
+	// TR: 2 e max(2, |12-9|, |10-9|) = 3
+	if !almostEqual(got[1], 2.5) {
+		t.Errorf("ATR esperado 2.5, recebido %f", got[1])
+	}
+}
+
+func TestParseSpecs(t *testing.T) {
+	specs, err := ParseSpecs("sma:20,ema:50,rsi:14,macd,bbands:20:2,atr:14")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{"sma:20", "ema:50", "rsi:14", "macd:12:26:9", "bbands:20:2", "atr:14"}
+	for i, key := range keys {
+		if specs[i].Key() != key {
+			t.Errorf("spec %d: esperado %s, recebido %s", i, key, specs[i].Key())
+		}
+	}
+
+	for _, invalid := range []string{"foo:1", "sma:abc", "sma:0", "sma:2.5", "rsi:14:2", ""} {
+		if _, err := ParseSpecs(invalid); err == nil {
+			t.Errorf("esperado erro para %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package indicators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Spec descreve um indicador no formato nome[:param[:param...]], por
+// exemplo sma:20, bbands:20:2 ou macd.
+type Spec struct {
+	Name   string
+	Params []float64
+}
+
+var defaultParams = map[string][]float64{
+	"sma":    {20},
+	"ema":    {20},
+	"rsi":    {14},
+	"macd":   {12, 26, 9},
+	"bbands": {20, 2},
+	"atr":    {14},
+}
+
+func ParseSpecs(value string) ([]Spec, error) {
+	var specs []Spec
+	for _, raw := range strings.Split(value, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		spec, err := ParseSpec(raw)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("nenhum indicador informado")
+	}
+	return specs, nil
+}
+
+func ParseSpec(raw string) (Spec, error) {
+	parts := strings.Split(strings.ToLower(raw), ":")
+	name := parts[0]
+
+	defaults, ok := defaultParams[name]
+	if !ok {
+		return Spec{}, fmt.Errorf("indicador desconhecido: %s", name)
+	}
+	if len(parts)-1 > len(defaults) {
+		return Spec{}, fmt.Errorf("parâmetros demais para %s", name)
+	}
+
+	params := append([]float64(nil), defaults...)
+	for i, part := range parts[1:] {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil || v <= 0 {
+			return Spec{}, fmt.Errorf("parâmetro inválido em %s: %s", raw, part)
+		}
+		params[i] = v
+	}
+
+	// só o multiplicador das bandas aceita valor fracionário
+	for i, v := range params {
+		if name == "bbands" && i == 1 {
+			continue
+		}
+		if v != float64(int(v)) {
+			return Spec{}, fmt.Errorf("período deve ser inteiro em %s", raw)
+		}
+	}
+
+	return Spec{Name: name, Params: params}, nil
+}
+
+// Key é a forma canônica da spec, com todos os parâmetros.
+func (s Spec) Key() string {
+	parts := []string{s.Name}
+	for _, p := range s.Params {
+		parts = append(parts, strconv.FormatFloat(p, 'f', -1, 64))
+	}
+	return strings.Join(parts, ":")
+}
+
+func (s Spec) period(i int) int {
+	return int(s.Params[i])
+}
+
+// Lookback é quantos pregões anteriores ao início da janela devem ser
+// carregados para que o indicador já esteja aquecido no primeiro pregão.
+// Indicadores recursivos (EMA, RSI, ATR, MACD) usam uma margem maior para
+// que o valor inicial da semente deixe de influenciar o resultado.
+func (s Spec) Lookback() int {
+	switch s.Name {
+	case "sma", "bbands":
+		return s.period(0) - 1
+	case "ema", "rsi", "atr":
+		return 4 * s.period(0)
+	case "macd":
+		return 4*s.period(1) + s.period(2)
+	}
+	return 0
+}
+
+// Compute calcula o indicador sobre os pregões; o mapa tem uma série por
+// saída do indicador, todas alinhadas a bars.
+func Compute(spec Spec, bars []Bar) map[string][]float64 {
+	closes := Closes(bars)
+
+	switch spec.Name {
+	case "sma":
+		return map[string][]float64{"value": SMA(closes, spec.period(0))}
+	case "ema":
+		return map[string][]float64{"value": EMA(closes, spec.period(0))}
+	case "rsi":
+		return map[string][]float64{"value": RSI(closes, spec.period(0))}
+	case "atr":
+		return map[string][]float64{"value": ATR(bars, spec.period(0))}
+	case "macd":
+		macd, signal, histogram := MACD(closes, spec.period(0), spec.period(1), spec.period(2))
+		return map[string][]float64{"macd": macd, "signal": signal, "histogram": histogram}
+	case "bbands":
+		upper, middle, lower := BollingerBands(closes, spec.period(0), spec.Params[1])
+		return map[string][]float64{"upper": upper, "middle": middle, "lower": lower}
+	}
+	return nil
+}
@@ -8,9 +8,11 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// Nop até Init ser chamado, para que serviços usados pela CLI possam logar
+// sem inicialização explícita.
 var (
-	Log   *zap.Logger
-	Sugar *zap.SugaredLogger
+	Log   = zap.NewNop()
+	Sugar = Log.Sugar()
 )
 
 func Init(level string, development bool) error {
@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Covariance retorna a covariância amostral de x e y, que devem ter o
+// mesmo tamanho.
+func Covariance(x, y []float64) float64 {
+	if len(x) != len(y) || len(x) < 2 {
+		return math.NaN()
+	}
+
+	mx, my := Mean(x), Mean(y)
+	sum := 0.0
+	for i := range x {
+		sum += (x[i] - mx) * (y[i] - my)
+	}
+	return sum / float64(len(x)-1)
+}
+
+func Pearson(x, y []float64) float64 {
+	sx, sy := StdDev(x), StdDev(y)
+	if sx == 0 || sy == 0 {
+		return math.NaN()
+	}
+	return Covariance(x, y) / (sx * sy)
+}
+
+// Spearman é a correlação de Pearson entre os postos, com empates
+// recebendo o posto médio.
+func Spearman(x, y []float64) float64 {
+	return Pearson(Ranks(x), Ranks(y))
+}
+
+// Beta de y em relação ao benchmark x: cov(x, y) / var(x).
+func Beta(benchmark, y []float64) float64 {
+	variance := Covariance(benchmark, benchmark)
+	if variance == 0 {
+		return math.NaN()
+	}
+	return Covariance(benchmark, y) / variance
+}
+
+func Ranks(values []float64) []float64 {
+	idx := make([]int, len(values))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return values[idx[a]] < values[idx[b]] })
+
+	ranks := make([]float64, len(values))
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && values[idx[j+1]] == values[idx[i]] {
+			j++
+		}
+		rank := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			ranks[idx[k]] = rank
+		}
+		i = j + 1
+	}
+	return ranks
+}
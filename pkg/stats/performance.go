@@ -0,0 +1,48 @@
+package stats
+
+import "math"
+
+// MaxDrawdown retorna a maior queda relativa de um pico até um vale
+// posterior (0.25 = -25%) e os índices do pico e do vale.
+func MaxDrawdown(values []float64) (float64, int, int) {
+	maxDD, peakIdx, troughIdx := 0.0, 0, 0
+	peak, currentPeakIdx := math.Inf(-1), 0
+
+	for i, v := range values {
+		if v > peak {
+			peak, currentPeakIdx = v, i
+			continue
+		}
+		if peak <= 0 {
+			continue
+		}
+		if dd := (peak - v) / peak; dd > maxDD {
+			maxDD, peakIdx, troughIdx = dd, currentPeakIdx, i
+		}
+	}
+	return maxDD, peakIdx, troughIdx
+}
+
+// Sharpe é o índice de Sharpe anualizado de retornos diários, com taxa
+// livre de risco diária rf.
+func Sharpe(returns []float64, rf float64) float64 {
+	excess := make([]float64, len(returns))
+	for i, r := range returns {
+		excess[i] = r - rf
+	}
+
+	sd := StdDev(excess)
+	if math.IsNaN(sd) || sd == 0 {
+		return math.NaN()
+	}
+	return Mean(excess) / sd * math.Sqrt(TradingDaysPerYear)
+}
+
+// CAGR é o retorno anual composto entre start e end ao longo de `days`
+// pregões.
+func CAGR(start, end float64, days int) float64 {
+	if start <= 0 || end < 0 || days <= 0 {
+		return math.NaN()
+	}
+	return math.Pow(end/start, TradingDaysPerYear/float64(days)) - 1
+}
@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+const TradingDaysPerYear = 252
+
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// StdDev retorna o desvio padrão amostral (n-1).
+func StdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return math.NaN()
+	}
+
+	mean := Mean(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
+
+// LogReturns retorna ln(p[i]/p[i-1]); o resultado tem len(prices)-1 itens.
+func LogReturns(prices []float64) []float64 {
+	if len(prices) < 2 {
+		return nil
+	}
+
+	returns := make([]float64, 0, len(prices)-1)
+	for i := 1; i < len(prices); i++ {
+		returns = append(returns, math.Log(prices[i]/prices[i-1]))
+	}
+	return returns
+}
+
+// SimpleReturns retorna p[i]/p[i-1] - 1; o resultado tem len(prices)-1 itens.
+func SimpleReturns(prices []float64) []float64 {
+	if len(prices) < 2 {
+		return nil
+	}
+
+	returns := make([]float64, 0, len(prices)-1)
+	for i := 1; i < len(prices); i++ {
+		returns = append(returns, prices[i]/prices[i-1]-1)
+	}
+	return returns
+}
+
+func Annualize(dailyVol float64) float64 {
+	return dailyVol * math.Sqrt(TradingDaysPerYear)
+}
+
+// Percentile interpola linearmente o percentil p (0-100) entre os valores
+// ordenados, sem alterar o slice recebido.
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
@@ -0,0 +1,115 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestStdDev(t *testing.T) {
+	got := StdDev([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+	if !almostEqual(got, math.Sqrt(32.0/7.0)) {
+		t.Errorf("desvio padrão amostral incorreto: %f", got)
+	}
+
+	if !math.IsNaN(StdDev([]float64{1})) {
+		t.Error("desvio padrão de um único valor deveria ser NaN")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{5, 1, 4, 2, 3}
+
+	if got := Percentile(values, 50); !almostEqual(got, 3) {
+		t.Errorf("mediana esperada 3, recebido %f", got)
+	}
+	if got := Percentile(values, 90); !almostEqual(got, 4.6) {
+		t.Errorf("p90 esperado 4.6, recebido %f", got)
+	}
+	if values[0] != 5 {
+		t.Error("Percentile não deveria ordenar o slice recebido")
+	}
+}
+
+func TestLogReturns(t *testing.T) {
+	returns := LogReturns([]float64{100, 110, 99})
+	if len(returns) != 2 {
+		t.Fatalf("esperado 2 retornos, recebido %d", len(returns))
+	}
+	if !almostEqual(returns[0], math.Log(1.1)) || !almostEqual(returns[1], math.Log(0.9)) {
+		t.Errorf("retornos incorretos: %v", returns)
+	}
+}
+
+func TestRangeEstimators(t *testing.T) {
+	bars := []OHLC{
+		{Open: 10, High: 11, Low: 9, Close: 10.5},
+		{Open: 10.5, High: 10.8, Low: 10, Close: 10},
+	}
+
+	hl1, hl2 := math.Log(11.0/9.0), math.Log(10.8/10.0)
+	expected := math.Sqrt((hl1*hl1 + hl2*hl2) / (4 * math.Ln2 * 2))
+	if got := Parkinson(bars); !almostEqual(got, expected) {
+		t.Errorf("Parkinson: esperado %f, recebido %f", expected, got)
+	}
+
+	co1, co2 := math.Log(10.5/10.0), math.Log(10.0/10.5)
+	k := 2*math.Ln2 - 1
+	expected = math.Sqrt((0.5*hl1*hl1 - k*co1*co1 + 0.5*hl2*hl2 - k*co2*co2) / 2)
+	if got := GarmanKlass(bars); !almostEqual(got, expected) {
+		t.Errorf("Garman-Klass: esperado %f, recebido %f", expected, got)
+	}
+}
+
+func TestDailyVolatilityInvalidMethod(t *testing.T) {
+	if _, _, err := DailyVolatility(nil, "ewma"); err == nil {
+		t.Error("esperado erro para método inválido")
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5}
+	y := []float64{2, 4, 6, 8, 10}
+
+	if got := Pearson(x, y); !almostEqual(got, 1) {
+		t.Errorf("Pearson esperado 1, recebido %f", got)
+	}
+	if got := Beta(x, y); !almostEqual(got, 2) {
+		t.Errorf("Beta esperado 2, recebido %f", got)
+	}
+
+	// relação monotônica não linear: Spearman 1, Pearson < 1
+	z := []float64{1, 8, 27, 64, 125}
+	if got := Spearman(x, z); !almostEqual(got, 1) {
+		t.Errorf("Spearman esperado 1, recebido %f", got)
+	}
+	if got := Pearson(x, z); got >= 1 {
+		t.Errorf("Pearson deveria ser menor que 1, recebido %f", got)
+	}
+}
+
+func TestRanksWithTies(t *testing.T) {
+	got := Ranks([]float64{10, 20, 10, 30})
+	expected := []float64{1.5, 3, 1.5, 4}
+
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("posto %d: esperado %f, recebido %f", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	dd, peak, trough := MaxDrawdown([]float64{100, 120, 90, 110, 130, 117})
+
+	if !almostEqual(dd, 0.25) || peak != 1 || trough != 2 {
+		t.Errorf("esperado 25%% entre 1 e 2, recebido %f entre %d e %d", dd, peak, trough)
+	}
+
+	if got := CAGR(100, 121, 2*TradingDaysPerYear); !almostEqual(got, 0.1) {
+		t.Errorf("CAGR esperado 10%%, recebido %f", got)
+	}
+}
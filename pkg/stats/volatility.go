@@ -0,0 +1,81 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	VolatilityCloseToClose = "close"
+	VolatilityParkinson    = "parkinson"
+	VolatilityGarmanKlass  = "garman_klass"
+)
+
+type OHLC struct {
+	Open  float64
+	High  float64
+	Low   float64
+	Close float64
+}
+
+func ValidVolatilityMethod(method string) bool {
+	switch method {
+	case VolatilityCloseToClose, VolatilityParkinson, VolatilityGarmanKlass:
+		return true
+	}
+	return false
+}
+
+// DailyVolatility estima a volatilidade diária pelo método escolhido e
+// retorna também quantos pregões entraram no cálculo. O método close usa o
+// desvio padrão dos log-retornos entre fechamentos; parkinson e
+// garman_klass usam o range de cada pregão.
+func DailyVolatility(bars []OHLC, method string) (float64, int, error) {
+	switch method {
+	case VolatilityCloseToClose:
+		closes := make([]float64, len(bars))
+		for i, bar := range bars {
+			closes[i] = bar.Close
+		}
+		return StdDev(LogReturns(closes)), len(bars), nil
+	case VolatilityParkinson:
+		return Parkinson(bars), len(bars), nil
+	case VolatilityGarmanKlass:
+		return GarmanKlass(bars), len(bars), nil
+	}
+	return math.NaN(), 0, fmt.Errorf("método de volatilidade inválido: %s", method)
+}
+
+// Parkinson usa apenas máxima e mínima: σ² = E[ln(H/L)²] / (4 ln 2).
+func Parkinson(bars []OHLC) float64 {
+	if len(bars) == 0 {
+		return math.NaN()
+	}
+
+	sum := 0.0
+	for _, bar := range bars {
+		hl := math.Log(bar.High / bar.Low)
+		sum += hl * hl
+	}
+	return math.Sqrt(sum / (4 * math.Ln2 * float64(len(bars))))
+}
+
+// GarmanKlass: σ² = E[½ ln(H/L)² − (2 ln 2 − 1) ln(C/O)²].
+func GarmanKlass(bars []OHLC) float64 {
+	if len(bars) == 0 {
+		return math.NaN()
+	}
+
+	sum := 0.0
+	for _, bar := range bars {
+		hl := math.Log(bar.High / bar.Low)
+		co := math.Log(bar.Close / bar.Open)
+		sum += 0.5*hl*hl - (2*math.Ln2-1)*co*co
+	}
+
+	variance := sum / float64(len(bars))
+	if variance < 0 {
+		return 0
+	}
+	return math.Sqrt(variance)
+}
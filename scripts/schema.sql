@@ -4,7 +4,16 @@ CREATE EXTENSION IF NOT EXISTS btree_gin;
 
 -- Remover tabela existente se houver
 DROP TABLE IF EXISTS trades CASCADE;
-DROP MATERIALIZED VIEW IF EXISTS daily_aggregations CASCADE;
+-- a antiga materialized view daily_aggregations cai junto com trades
+DROP TABLE IF EXISTS daily_aggregations CASCADE;
+DROP TABLE IF EXISTS alert_events CASCADE;
+DROP TABLE IF EXISTS alert_rules CASCADE;
+DROP TABLE IF EXISTS portfolio_positions CASCADE;
+DROP TABLE IF EXISTS portfolios CASCADE;
+DROP TABLE IF EXISTS realized_volatility CASCADE;
+DROP TABLE IF EXISTS custom_index_levels CASCADE;
+DROP TABLE IF EXISTS custom_index_constituents CASCADE;
+DROP TABLE IF EXISTS custom_indexes CASCADE;
 
 -- Tabela principal particionada
 CREATE TABLE trades (
@@ -38,25 +47,149 @@ CREATE INDEX trades_2025_05_gin_idx ON trades_2025_05 USING gin (codigo_instrume
 CREATE INDEX trades_2025_06_gin_idx ON trades_2025_06 USING gin (codigo_instrumento, data_negocio, preco_negocio);
 CREATE INDEX trades_2025_07_gin_idx ON trades_2025_07 USING gin (codigo_instrumento, data_negocio, preco_negocio);
 
--- Materialized View para agregações
-CREATE MATERIALIZED VIEW daily_aggregations AS
-SELECT
-    codigo_instrumento,
-    data_negocio,
-    MAX(preco_negocio) as max_price,
-    SUM(quantidade_negociada) as total_volume,
-    COUNT(*) as trade_count,
-    MIN(preco_negocio) as min_price,
-    AVG(preco_negocio) as avg_price,
-    STDDEV(preco_negocio) as price_stddev
-FROM trades
-GROUP BY codigo_instrumento, data_negocio;
-
--- Índice único para refresh concorrente
-CREATE UNIQUE INDEX daily_agg_unique_idx
-ON daily_aggregations(codigo_instrumento, data_negocio);
+-- Agregações diárias, mantidas pela carga apenas para os pregões tocados
+-- (ingestion.RefreshDailyAggregations); `b3-analyzer-cli refresh` reconstrói
+-- a tabela inteira a partir de trades
+CREATE TABLE daily_aggregations (
+    codigo_instrumento VARCHAR(20) NOT NULL,
+    data_negocio DATE NOT NULL,
+    max_price DECIMAL(10, 2) NOT NULL,
+    total_volume BIGINT NOT NULL,
+    trade_count BIGINT NOT NULL,
+    min_price DECIMAL(10, 2) NOT NULL,
+    avg_price NUMERIC NOT NULL,
+    price_stddev NUMERIC,
+    open_price DECIMAL(10, 2) NOT NULL,
+    close_price DECIMAL(10, 2) NOT NULL,
+    financial_volume NUMERIC NOT NULL,
+    vwap NUMERIC,
+    -- negócios em bloco: financeiro a partir de R$ 1 milhão (ingestion.BlockTradeMinFinancial)
+    block_volume BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (codigo_instrumento, data_negocio)
+);
 
 -- Índices adicionais para performance
 CREATE INDEX daily_agg_ticker_idx ON daily_aggregations(codigo_instrumento);
 CREATE INDEX daily_agg_date_idx ON daily_aggregations(data_negocio);
-CREATE INDEX daily_agg_volume_idx ON daily_aggregations(total_volume DESC);
\ No newline at end of file
+CREATE INDEX daily_agg_volume_idx ON daily_aggregations(total_volume DESC);
+
+-- Regras de alerta avaliadas após cada carga/refresh
+CREATE TABLE alert_rules (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    codigo_instrumento VARCHAR(20) NOT NULL,
+    condition VARCHAR(30) NOT NULL,
+    threshold DECIMAL(18, 4) NOT NULL,
+    lookback INT NOT NULL DEFAULT 20,
+    webhook_url TEXT,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX alert_rules_active_idx ON alert_rules(active);
+
+-- Histórico de disparos; uma regra dispara no máximo uma vez por pregão
+CREATE TABLE alert_events (
+    id BIGSERIAL PRIMARY KEY,
+    rule_id BIGINT NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+    codigo_instrumento VARCHAR(20) NOT NULL,
+    data_negocio DATE NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    message TEXT NOT NULL,
+    triggered_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivery_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    delivery_attempts INT NOT NULL DEFAULT 0,
+    claimed_at TIMESTAMP,
+    delivered_at TIMESTAMP,
+    last_error TEXT,
+    UNIQUE (rule_id, data_negocio)
+);
+
+CREATE INDEX alert_events_triggered_idx ON alert_events(triggered_at DESC);
+CREATE INDEX alert_events_status_idx ON alert_events(delivery_status);
+
+-- Carteiras e suas posições (uma linha por lote)
+CREATE TABLE portfolios (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE portfolio_positions (
+    id BIGSERIAL PRIMARY KEY,
+    portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    codigo_instrumento VARCHAR(20) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity <> 0),
+    cost_basis DECIMAL(18, 4) NOT NULL,
+    trade_date DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX portfolio_positions_portfolio_idx ON portfolio_positions(portfolio_id);
+
+-- Volatilidade realizada diária a partir de retornos intradiários,
+-- calculada sob demanda por ticker e frequência de amostragem; as cargas
+-- removem os pregões que tocam para que sejam recalculados
+CREATE TABLE realized_volatility (
+    codigo_instrumento VARCHAR(20) NOT NULL,
+    data_negocio DATE NOT NULL,
+    frequency_seconds INT NOT NULL,
+    observations INT NOT NULL,
+    realized_variance DOUBLE PRECISION NOT NULL,
+    realized_volatility DOUBLE PRECISION NOT NULL,
+    computed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (codigo_instrumento, frequency_seconds, data_negocio)
+);
+
+-- Índices customizados sobre cestas de tickers
+CREATE TABLE custom_indexes (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(16) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    weighting VARCHAR(10) NOT NULL,
+    rebalance VARCHAR(10) NOT NULL,
+    base_date DATE NOT NULL,
+    base_value DECIMAL(18, 6) NOT NULL DEFAULT 1000,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE custom_index_constituents (
+    index_id BIGINT NOT NULL REFERENCES custom_indexes(id) ON DELETE CASCADE,
+    codigo_instrumento VARCHAR(20) NOT NULL,
+    weight DECIMAL(18, 8),
+    PRIMARY KEY (index_id, codigo_instrumento)
+);
+
+CREATE TABLE custom_index_levels (
+    index_id BIGINT NOT NULL REFERENCES custom_indexes(id) ON DELETE CASCADE,
+    data_negocio DATE NOT NULL,
+    open_level DECIMAL(18, 6) NOT NULL,
+    close_level DECIMAL(18, 6) NOT NULL,
+    total_volume BIGINT NOT NULL,
+    financial_volume DECIMAL(20, 2) NOT NULL,
+    trade_count BIGINT NOT NULL,
+    rebalanced BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (index_id, data_negocio)
+);
+
+-- Níveis dos índices no formato de daily_aggregations, consultados com o
+-- prefixo IDX: nos endpoints de histórico e estatísticas
+CREATE VIEW index_daily_aggregations AS
+SELECT
+    'IDX:' || i.code as codigo_instrumento,
+    l.data_negocio,
+    GREATEST(l.open_level, l.close_level) as max_price,
+    l.total_volume,
+    l.trade_count,
+    LEAST(l.open_level, l.close_level) as min_price,
+    (l.open_level + l.close_level) / 2 as avg_price,
+    NULL::numeric as price_stddev,
+    l.open_level as open_price,
+    l.close_level as close_price,
+    l.financial_volume,
+    NULL::numeric as vwap,
+    0::bigint as block_volume
+FROM custom_index_levels l
+JOIN custom_indexes i ON i.id = l.index_id;